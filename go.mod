@@ -5,6 +5,7 @@ go 1.22.5
 toolchain go1.23.4
 
 require (
+	github.com/cenkalti/backoff v2.2.1+incompatible
 	github.com/sirupsen/logrus v1.9.3
 	github.com/spf13/cobra v1.8.1
 	github.com/spf13/viper v1.19.0
@@ -14,7 +15,6 @@ require (
 )
 
 require (
-	github.com/cenkalti/backoff v2.2.1+incompatible // indirect
 	github.com/fsnotify/fsnotify v1.7.0 // indirect
 	github.com/gorilla/websocket v1.5.2 // indirect
 	github.com/grandcat/zeroconf v1.0.0 // indirect
@@ -2,7 +2,9 @@ package denonavrclient
 
 import (
 	"fmt"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -20,7 +22,19 @@ type DenonAVRClient struct {
 	moni2Button    *entities.ButtonEntity
 	moniAutoButton *entities.ButtonEntity
 
-	mediaPlayer *entities.MediaPlayerEntity
+	quickSelectButtons []*entities.ButtonEntity
+
+	// Audyssey
+	dynamicEQSwitch     *entities.SwitchsEntity
+	dynamicVolumeSensor *entities.SensorEntity
+	nightModeButton     *entities.ButtonEntity
+
+	dimmerSensor *entities.SensorEntity
+
+	// Decoder settings, by the attribute of the setting
+	decoderSettingSensors map[string]*entities.SensorEntity
+
+	mediaPlayers map[denonavr.DenonZone]*entities.MediaPlayerEntity
 
 	mapOnState map[bool]entities.MediaPlayerEntityState
 }
@@ -37,7 +51,7 @@ func NewDenonAVRClient(i *integration.Integration) *DenonAVRClient {
 	inputSetting_ipaddr := integration.SetupDataSchemaSettings{
 		Id: "ipaddr",
 		Label: integration.LanguageText{
-			En: "IP Address of your Denon Receiver",
+			En: "IP Address of your Denon Receiver (leave empty to search your network)",
 		},
 		Field: integration.SettingTypeText{
 			Text: integration.SettingTypeTextDefinition{
@@ -58,6 +72,60 @@ func NewDenonAVRClient(i *integration.Integration) *DenonAVRClient {
 		},
 	}
 
+	inputSetting_telnetonly := integration.SetupDataSchemaSettings{
+		Id: "telnetonly",
+		Label: integration.LanguageText{
+			En: "Only use telnet to get the state of your DenonAVR Device (no HTTP polling)",
+		},
+		Field: integration.SettingTypeCheckbox{
+			Checkbox: integration.SettingTypeCheckboxDefinition{
+				Value: false,
+			},
+		},
+	}
+
+	inputSetting_zone2 := integration.SetupDataSchemaSettings{
+		Id: "zone2",
+		Label: integration.LanguageText{
+			En: "Add a media player for Zone 2",
+		},
+		Field: integration.SettingTypeCheckbox{
+			Checkbox: integration.SettingTypeCheckboxDefinition{
+				Value: false,
+			},
+		},
+	}
+
+	inputSetting_zone3 := integration.SetupDataSchemaSettings{
+		Id: "zone3",
+		Label: integration.LanguageText{
+			En: "Add a media player for Zone 3",
+		},
+		Field: integration.SettingTypeCheckbox{
+			Checkbox: integration.SettingTypeCheckboxDefinition{
+				Value: false,
+			},
+		},
+	}
+
+	inputSetting_ecomode := integration.SetupDataSchemaSettings{
+		Id: "ecomode",
+		Label: integration.LanguageText{
+			En: "ECO mode of your Denon Receiver",
+		},
+		Field: integration.SettingTypeDropdown{
+			Dropdown: integration.SettingTypeDropdowDefinition{
+				Value: "",
+				Items: []integration.SettingTypeDropdowItemsDefinition{
+					{Id: "", Label: integration.LanguageText{En: "Don't change"}},
+					{Id: string(denonavr.DenonECOModeOn), Label: integration.LanguageText{En: "On"}},
+					{Id: string(denonavr.DenonECOModeAuto), Label: integration.LanguageText{En: "Auto"}},
+					{Id: string(denonavr.DenonECOModeOff), Label: integration.LanguageText{En: "Off"}},
+				},
+			},
+		},
+	}
+
 	metadata := integration.DriverMetadata{
 		DriverId: "denonavr",
 		Developer: integration.Developer{
@@ -72,7 +140,7 @@ func NewDenonAVRClient(i *integration.Integration) *DenonAVRClient {
 				En: "Configuration",
 				De: "Konfiguration",
 			},
-			Settings: []integration.SetupDataSchemaSettings{inputSetting_ipaddr, inputSetting_telnet},
+			Settings: []integration.SetupDataSchemaSettings{inputSetting_ipaddr, inputSetting_telnet, inputSetting_telnetonly, inputSetting_zone2, inputSetting_zone3, inputSetting_ecomode},
 		},
 		Icon: "custom:denon.png",
 	}
@@ -85,6 +153,8 @@ func NewDenonAVRClient(i *integration.Integration) *DenonAVRClient {
 	client.ClientLoopFunc = client.denonClientLoop
 	client.SetDriverUserDataFunc = client.handleSetDriverUserData
 
+	client.mediaPlayers = make(map[denonavr.DenonZone]*entities.MediaPlayerEntity)
+
 	client.mapOnState = map[bool]entities.MediaPlayerEntityState{
 		true:  entities.OnMediaPlayerEntityState,
 		false: entities.OffMediaPlayerEntityState,
@@ -97,24 +167,19 @@ func (c *DenonAVRClient) initDenonAVRClient() {
 
 	log.Debug("Initialize DenonAVR CLient")
 
-	// Media Player
-	c.mediaPlayer = entities.NewMediaPlayerEntity("mediaplayer", entities.LanguageText{En: "Denon AVR"}, "", entities.ReceiverMediaPlayerDeviceClass)
-	c.mediaPlayer.AddFeature(entities.OnOffMediaPlayerEntityFeatures)
-	c.mediaPlayer.AddFeature(entities.ToggleMediaPlayerEntityyFeatures)
-	c.mediaPlayer.AddFeature(entities.VolumeMediaPlayerEntityyFeatures)
-	c.mediaPlayer.AddFeature(entities.VolumeUpDownMediaPlayerEntityFeatures)
-	c.mediaPlayer.AddFeature(entities.MuteMediaPlayerEntityFeatures)
-	c.mediaPlayer.AddFeature(entities.UnmuteMediaPlayerEntityFeatures)
-	c.mediaPlayer.AddFeature(entities.MuteToggleMediaPlayerEntityFeatures)
-	c.mediaPlayer.AddFeature(entities.SelectSourceMediaPlayerEntityFeatures)
-	c.mediaPlayer.AddFeature(entities.SelectSoundModeMediaPlayerEntityFeatures)
-	c.mediaPlayer.AddFeature(entities.DPadMediaPlayerEntityFeatures)
-	c.mediaPlayer.AddFeature(entities.MediaTitleMediaPlayerEntityFeatures)
-	c.mediaPlayer.AddFeature(entities.MediaImageUrlMediaPlayerEntityFeatures)
-	c.mediaPlayer.AddFeature(entities.MenuMediaPlayerEntityFeatures)
-	c.mediaPlayer.AddFeature(entities.InfoPlayerEntityFeatures)
-
-	if err := c.IntegrationDriver.AddEntity(c.mediaPlayer); err != nil {
+	// Media Player of the main zone
+	mainZoneMediaPlayer := c.newMediaPlayer(denonavr.MainZone)
+	c.mediaPlayers[denonavr.MainZone] = mainZoneMediaPlayer
+
+	// Features only available on the main zone
+	mainZoneMediaPlayer.AddFeature(entities.DPadMediaPlayerEntityFeatures)
+	mainZoneMediaPlayer.AddFeature(entities.MediaTitleMediaPlayerEntityFeatures)
+	mainZoneMediaPlayer.AddFeature(entities.MediaAlbumMediaPlayerEntityFeatures)
+	mainZoneMediaPlayer.AddFeature(entities.MediaImageUrlMediaPlayerEntityFeatures)
+	mainZoneMediaPlayer.AddFeature(entities.MenuMediaPlayerEntityFeatures)
+	mainZoneMediaPlayer.AddFeature(entities.InfoPlayerEntityFeatures)
+
+	if err := c.IntegrationDriver.AddEntity(mainZoneMediaPlayer); err != nil {
 		log.WithError(err).Error("Cannot add Entity")
 	}
 
@@ -134,16 +199,288 @@ func (c *DenonAVRClient) initDenonAVRClient() {
 		log.WithError(err).Error("Cannot add Entity")
 	}
 
-	simpleCommands := []string{"OUTPUT_MONITOR1", "OUTPUT_MONITOR2", "OUTPUT_MONITORAUTO"}
+	for slot := 1; slot <= denonavr.QUICK_SELECT_SLOTS; slot++ {
+		quickSelectButton := entities.NewButtonEntity(fmt.Sprintf("quickselect%d", slot), entities.LanguageText{En: fmt.Sprintf("Quick Select %d", slot)}, "")
+		if err := c.IntegrationDriver.AddEntity(quickSelectButton); err != nil {
+			log.WithError(err).Error("Cannot add Entity")
+		}
+		c.quickSelectButtons = append(c.quickSelectButtons, quickSelectButton)
+	}
 
-	c.mediaPlayer.AddOption(entities.SimpleCommandsMediaPlayerEntityOption, simpleCommands)
+	// Audyssey
+	c.dynamicEQSwitch = entities.NewSwitchEntity("dynamiceq", entities.LanguageText{En: "Dynamic EQ"}, "")
+	c.dynamicEQSwitch.AddFeature(entities.OnOffSwitchEntityyFeatures)
+	c.dynamicEQSwitch.AddFeature(entities.ToggleSwitchEntityyFeatures)
+	if err := c.IntegrationDriver.AddEntity(c.dynamicEQSwitch); err != nil {
+		log.WithError(err).Error("Cannot add Entity")
+	}
 
+	c.dynamicVolumeSensor = entities.NewSensorEntity("dynamicvolume", entities.LanguageText{En: "Dynamic Volume"}, "", entities.CustomSensorDeviceClass)
+	if err := c.IntegrationDriver.AddEntity(c.dynamicVolumeSensor); err != nil {
+		log.WithError(err).Error("Cannot add Entity")
+	}
+
+	// Cycles through the dynamic volume settings
+	c.nightModeButton = entities.NewButtonEntity("nightmode", entities.LanguageText{En: "Night Mode"}, "")
+	if err := c.IntegrationDriver.AddEntity(c.nightModeButton); err != nil {
+		log.WithError(err).Error("Cannot add Entity")
+	}
+
+	// Front panel display
+	c.dimmerSensor = entities.NewSensorEntity("dimmer", entities.LanguageText{En: "Display Dimmer"}, "", entities.CustomSensorDeviceClass)
+	if err := c.IntegrationDriver.AddEntity(c.dimmerSensor); err != nil {
+		log.WithError(err).Error("Cannot add Entity")
+	}
+
+	// Decoder settings
+	c.decoderSettingSensors = make(map[string]*entities.SensorEntity)
+	for _, decoderSetting := range []struct {
+		attribute string
+		id        string
+		name      string
+	}{
+		{"DynamicRangeCompression", "drc", "Dynamic Range Compression"},
+		{"LFELevel", "lfelevel", "LFE Level"},
+		{"CinemaEQ", "cinemaeq", "Cinema EQ"},
+		{"LoudnessManagement", "loudnessmanagement", "Loudness Management"},
+	} {
+		sensor := entities.NewSensorEntity(decoderSetting.id, entities.LanguageText{En: decoderSetting.name}, "", entities.CustomSensorDeviceClass)
+		if err := c.IntegrationDriver.AddEntity(sensor); err != nil {
+			log.WithError(err).Error("Cannot add Entity")
+		}
+		c.decoderSettingSensors[decoderSetting.attribute] = sensor
+	}
+
+	// The main zone supports the simple commands of all zones and its own
+	simpleCommands := append(c.getZoneSimpleCommands(),
+		"OUTPUT_MONITOR1", "OUTPUT_MONITOR2", "OUTPUT_MONITORAUTO",
+		"CENTER_UP", "CENTER_DOWN", "SUB_UP", "SUB_DOWN", "CHANNEL_LEVEL_RESET",
+		"BASS_UP", "BASS_DOWN", "TREBLE_UP", "TREBLE_DOWN", "TONE_DEFEAT_ON", "TONE_DEFEAT_OFF",
+		"MULTEQ_AUDYSSEY", "MULTEQ_BYPASS_LR", "MULTEQ_FLAT", "MULTEQ_MANUAL", "MULTEQ_OFF",
+		"DYNAMIC_EQ_ON", "DYNAMIC_EQ_OFF",
+		"REFERENCE_LEVEL_0", "REFERENCE_LEVEL_5", "REFERENCE_LEVEL_10", "REFERENCE_LEVEL_15",
+		"DYNAMIC_VOLUME_OFF", "DYNAMIC_VOLUME_LIGHT", "DYNAMIC_VOLUME_MEDIUM", "DYNAMIC_VOLUME_HEAVY", "NIGHT_MODE",
+		"DIALOG_LEVEL_ON", "DIALOG_LEVEL_OFF", "DIALOG_LEVEL_UP", "DIALOG_LEVEL_DOWN",
+		"DIALOG_ENHANCER_OFF", "DIALOG_ENHANCER_LOW", "DIALOG_ENHANCER_MEDIUM", "DIALOG_ENHANCER_HIGH",
+		"SUBWOOFER_LEVEL_ON", "SUBWOOFER_LEVEL_OFF", "SUBWOOFER_LEVEL_UP", "SUBWOOFER_LEVEL_DOWN",
+		"DIRAC_OFF", "DIRAC_SLOT1", "DIRAC_SLOT2", "DIRAC_SLOT3", "SPEAKER_PRESET_1", "SPEAKER_PRESET_2",
+		"DRC_AUTO", "DRC_LOW", "DRC_MID", "DRC_HIGH", "DRC_OFF", "LFE_UP", "LFE_DOWN",
+		"CINEMA_EQ_ON", "CINEMA_EQ_OFF", "LOUDNESS_MANAGEMENT_ON", "LOUDNESS_MANAGEMENT_OFF",
+		"SPEAKER_VIRTUALIZER_ON", "SPEAKER_VIRTUALIZER_OFF", "NEURAL_X_ON", "NEURAL_X_OFF",
+		"CENTER_SPREAD_ON", "CENTER_SPREAD_OFF", "HEIGHT_VIRTUALIZATION_ON", "HEIGHT_VIRTUALIZATION_OFF",
+		"ECO_ON", "ECO_AUTO", "ECO_OFF",
+		"DIMMER_BRIGHT", "DIMMER_DIM", "DIMMER_DARK", "DIMMER_OFF", "DIMMER_CYCLE",
+		"QUICK_SELECT_1_MEMORY", "QUICK_SELECT_2_MEMORY", "QUICK_SELECT_3_MEMORY", "QUICK_SELECT_4_MEMORY", "QUICK_SELECT_5_MEMORY",
+		"TUNER_BAND_AM", "TUNER_BAND_FM", "TUNER_FREQUENCY_UP", "TUNER_FREQUENCY_DOWN", "TUNER_PRESET_UP", "TUNER_PRESET_DOWN",
+	)
+
+	mainZoneMediaPlayer.AddOption(entities.SimpleCommandsMediaPlayerEntityOption, simpleCommands)
+
+	// Media Player for the other zones enabled in the setup, before the remote asks for the available entities
+	c.addZoneMediaPlayers()
+}
+
+// Return the zones a media player is created for
+// The main zone is always enabled, Zone2 and Zone3 can be enabled in the setup if the receiver has them
+func (c *DenonAVRClient) getEnabledZones() []denonavr.DenonZone {
+
+	zones := []denonavr.DenonZone{denonavr.MainZone}
+
+	if enabled, err := strconv.ParseBool(c.IntegrationDriver.SetupData["zone2"]); err == nil && enabled && c.hasZone(denonavr.Zone2) {
+		zones = append(zones, denonavr.Zone2)
+	}
+
+	if enabled, err := strconv.ParseBool(c.IntegrationDriver.SetupData["zone3"]); err == nil && enabled && c.hasZone(denonavr.Zone3) {
+		zones = append(zones, denonavr.Zone3)
+	}
+
+	return zones
+}
+
+// Return if the receiver has a zone, all zones are assumed if the device info is not available
+func (c *DenonAVRClient) hasZone(zone denonavr.DenonZone) bool {
+
+	if c.denon == nil || c.denon.GetDeviceInfo() == nil {
+		return true
+	}
+
+	return c.denon.GetDeviceInfo().HasZone(zone)
+}
+
+// Add the media players of the enabled zones and remove the ones of zones no longer enabled or not available
+func (c *DenonAVRClient) addZoneMediaPlayers() {
+
+	enabledZones := c.getEnabledZones()
+
+	for _, zone := range enabledZones {
+		if _, ok := c.mediaPlayers[zone]; ok {
+			continue
+		}
+		c.mediaPlayers[zone] = c.newMediaPlayer(zone)
+		if err := c.IntegrationDriver.AddEntity(c.mediaPlayers[zone]); err != nil {
+			log.WithError(err).Error("Cannot add Entity")
+		}
+	}
+
+	for zone, mediaPlayer := range c.mediaPlayers {
+		if slices.Contains(enabledZones, zone) {
+			continue
+		}
+		if err := c.IntegrationDriver.RemoveEntity(mediaPlayer); err != nil {
+			log.WithError(err).Error("Cannot remove Entity")
+		}
+		delete(c.mediaPlayers, zone)
+	}
+}
+
+// Adjust the entities and commands to the capabilities of the receiver
+// Called on each connect, the media players of zones the receiver does not have are removed
+func (c *DenonAVRClient) configureDeviceCapabilities() {
+
+	if _, err := c.denon.UpdateDeviceInfo(); err != nil {
+		log.WithError(err).Info("Device info not available, assume all capabilities")
+	}
+
+	c.addZoneMediaPlayers()
+
+	// Tuner commands only for receivers with a tuner
+	deviceInfo := c.denon.GetDeviceInfo()
+	if deviceInfo != nil && !deviceInfo.SupportsInputFunction(denonavr.MainZone, "TUNER") {
+		mainZoneMediaPlayer := c.mediaPlayers[denonavr.MainZone]
+		var simpleCommands []string
+		for _, command := range mainZoneMediaPlayer.Options[entities.SimpleCommandsMediaPlayerEntityOption].([]string) {
+			if !strings.HasPrefix(command, "TUNER_") {
+				simpleCommands = append(simpleCommands, command)
+			}
+		}
+		mainZoneMediaPlayer.AddOption(entities.SimpleCommandsMediaPlayerEntityOption, simpleCommands)
+	}
+}
+
+// Create a new media player entity for a zone with the features all zones support
+func (c *DenonAVRClient) newMediaPlayer(zone denonavr.DenonZone) *entities.MediaPlayerEntity {
+
+	var mediaPlayer *entities.MediaPlayerEntity
+
+	switch zone {
+	case denonavr.Zone2:
+		mediaPlayer = entities.NewMediaPlayerEntity("mediaplayer_zone2", entities.LanguageText{En: "Denon AVR Zone 2"}, "", entities.ReceiverMediaPlayerDeviceClass)
+	case denonavr.Zone3:
+		mediaPlayer = entities.NewMediaPlayerEntity("mediaplayer_zone3", entities.LanguageText{En: "Denon AVR Zone 3"}, "", entities.ReceiverMediaPlayerDeviceClass)
+	default:
+		mediaPlayer = entities.NewMediaPlayerEntity("mediaplayer", entities.LanguageText{En: "Denon AVR"}, "", entities.ReceiverMediaPlayerDeviceClass)
+	}
+
+	mediaPlayer.AddFeature(entities.OnOffMediaPlayerEntityFeatures)
+	mediaPlayer.AddFeature(entities.ToggleMediaPlayerEntityyFeatures)
+	mediaPlayer.AddFeature(entities.VolumeMediaPlayerEntityyFeatures)
+	mediaPlayer.AddFeature(entities.VolumeUpDownMediaPlayerEntityFeatures)
+	mediaPlayer.AddFeature(entities.MuteMediaPlayerEntityFeatures)
+	mediaPlayer.AddFeature(entities.UnmuteMediaPlayerEntityFeatures)
+	mediaPlayer.AddFeature(entities.MuteToggleMediaPlayerEntityFeatures)
+	mediaPlayer.AddFeature(entities.SelectSourceMediaPlayerEntityFeatures)
+	mediaPlayer.AddFeature(entities.SelectSoundModeMediaPlayerEntityFeatures)
+
+	mediaPlayer.AddOption(entities.SimpleCommandsMediaPlayerEntityOption, c.getZoneSimpleCommands())
+
+	return mediaPlayer
+}
+
+// Return the simple commands all zones support
+func (c *DenonAVRClient) getZoneSimpleCommands() []string {
+	return []string{"SLEEP_30", "SLEEP_60", "SLEEP_OFF"}
 }
 
 func (c *DenonAVRClient) denonHandleSetup(setup_data integration.SetupData) {
 
 	c.IntegrationDriver.SetDriverSetupState(integration.SetupEvent, integration.SetupState, "", nil)
 
+	if c.IntegrationDriver.SetupData["ipaddr"] == "" {
+		c.handleDiscovery()
+		return
+	}
+
+	c.handleDeviceSetup()
+}
+
+// Search receivers in the network and let the user choose one
+func (c *DenonAVRClient) handleDiscovery() {
+
+	devices, err := denonavr.DiscoverDevices(denonavr.SSDP_DISCOVERY_TIMEOUT)
+	if err != nil {
+		log.WithError(err).Error("Cannot search for Denon AVR devices")
+	}
+
+	var items []integration.SettingTypeDropdowItemsDefinition
+	for _, device := range devices {
+		items = append(items, integration.SettingTypeDropdowItemsDefinition{
+			Id: device.Host,
+			Label: integration.LanguageText{
+				En: fmt.Sprintf("%s (%s) - %s", device.FriendlyName, device.ModelName, device.Host),
+			},
+		})
+	}
+
+	// Manual entry as fallback
+	items = append(items, integration.SettingTypeDropdowItemsDefinition{
+		Id: "",
+		Label: integration.LanguageText{
+			En: "Enter the IP address manually",
+		},
+	})
+
+	var userAction = integration.RequireUserAction{
+		Input: integration.SettigsPage{
+			Title: integration.LanguageText{
+				En: "Select your Denon Receiver",
+			},
+			Settings: []integration.Setting{
+				{
+					Id: "discovered",
+					Label: integration.LanguageText{
+						En: "Discovered Receivers",
+					},
+					Field: integration.SettingTypeDropdown{
+						Dropdown: integration.SettingTypeDropdowDefinition{
+							Value: items[0].Id,
+							Items: items,
+						},
+					},
+				},
+				{
+					Id: "manual_ipaddr",
+					Label: integration.LanguageText{
+						En: "IP Address of your Denon Receiver",
+					},
+					Field: integration.SettingTypeText{
+						Text: integration.SettingTypeTextDefinition{
+							Value: "",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	c.IntegrationDriver.SetDriverSetupState(integration.SetupEvent, integration.WaitUserActionState, "", &userAction)
+}
+
+// Check the receiver at the configured ip address and ask for the telnet confirmation
+func (c *DenonAVRClient) handleDeviceSetup() {
+
+	// Newer receivers serve the control API on another port or with HTTPS
+	// The detected url is persisted when the setup is finished
+	// If the receiver is not reachable yet, http://<ipaddr> is used and the detection is retried on the next start
+	if baseURL, err := denonavr.DetectBaseURL(c.IntegrationDriver.SetupData["ipaddr"]); err == nil {
+		c.IntegrationDriver.SetupData["baseurl"] = baseURL
+	} else {
+		log.WithError(err).Warn("Cannot find the control API of the Denon AVR, use http://" + c.IntegrationDriver.SetupData["ipaddr"])
+		c.IntegrationDriver.SetupData["baseurl"] = ""
+	}
+
+	// The remote asks for the available entities when the setup is finished
+	c.addZoneMediaPlayers()
+
 	telnetEnabled, err := strconv.ParseBool(c.IntegrationDriver.SetupData["telnet"])
 	if err != nil {
 		telnetEnabled = false
@@ -177,6 +514,24 @@ func (c *DenonAVRClient) handleSetDriverUserData(user_data map[string]string, co
 
 	log.Debug("Denon handle set driver user data")
 
+	// Answer of the discovery page
+	if _, ok := user_data["discovered"]; ok {
+		ipaddr := user_data["discovered"]
+		if ipaddr == "" {
+			ipaddr = strings.TrimSpace(user_data["manual_ipaddr"])
+		}
+
+		if ipaddr == "" {
+			log.Error("No Denon AVR selected or entered")
+			c.IntegrationDriver.SetDriverSetupState(integration.StopEvent, integration.ErrorState, integration.NotFoundError, nil)
+			return
+		}
+
+		c.IntegrationDriver.SetupData["ipaddr"] = ipaddr
+		c.handleDeviceSetup()
+		return
+	}
+
 	// confirm seems to be set to false always, maybe just the presence of the field tells me,
 	// confirmation was sent?
 	if len(user_data) == 0 {
@@ -188,7 +543,7 @@ func (c *DenonAVRClient) handleSetDriverUserData(user_data map[string]string, co
 		}
 
 		if telnetEnabled {
-			denon := denonavr.NewDenonAVR(c.IntegrationDriver.SetupData["ipaddr"], telnetEnabled)
+			denon := denonavr.NewDenonAVR(c.IntegrationDriver.SetupData["ipaddr"], telnetEnabled, false)
 			if telnet, err := denon.ConnectTelnet(); err != nil {
 				c.IntegrationDriver.SetDriverSetupState(integration.StopEvent, integration.ErrorState, integration.ConnectionRefusedError, nil)
 				return
@@ -213,7 +568,22 @@ func (c *DenonAVRClient) setupDenon() error {
 		if err != nil {
 			telnetEnabled = false
 		}
-		c.denon = denonavr.NewDenonAVR(c.IntegrationDriver.SetupData["ipaddr"], telnetEnabled)
+		telnetOnly, err := strconv.ParseBool(c.IntegrationDriver.SetupData["telnetonly"])
+		if err != nil {
+			telnetOnly = false
+		}
+		c.denon = denonavr.NewDenonAVR(c.IntegrationDriver.SetupData["ipaddr"], telnetEnabled, telnetOnly)
+
+		// Detect the control API if the setup was done before the detection was available or the receiver was not reachable
+		if c.IntegrationDriver.SetupData["baseurl"] == "" {
+			if baseURL, err := denonavr.DetectBaseURL(c.IntegrationDriver.SetupData["ipaddr"]); err == nil {
+				c.IntegrationDriver.SetupData["baseurl"] = baseURL
+				c.IntegrationDriver.PersistSetupData()
+			}
+		}
+		if c.IntegrationDriver.SetupData["baseurl"] != "" {
+			c.denon.BaseURL = c.IntegrationDriver.SetupData["baseurl"]
+		}
 	} else {
 		err := fmt.Errorf("cannot setup Denon Client, missing setupData")
 		return err
@@ -233,132 +603,332 @@ func (c *DenonAVRClient) configureDenon() {
 	c.moni2Button.MapCommand(entities.PushButtonEntityCommand, c.denon.SetMoni2Out)
 	c.moniAutoButton.MapCommand(entities.PushButtonEntityCommand, c.denon.SetMoniAutoOut)
 
-	c.mediaPlayer.MapCommand(entities.MediaPlayerEntityCommand("OUTPUT_MONITOR1"), c.denon.SetMoni1Out)
-	c.mediaPlayer.MapCommand(entities.MediaPlayerEntityCommand("OUTPUT_MONITOR2"), c.denon.SetMoni2Out)
-	c.mediaPlayer.MapCommand(entities.MediaPlayerEntityCommand("OUTPUT_MONITORAUTO"), c.denon.SetMoniAutoOut)
+	for i, quickSelectButton := range c.quickSelectButtons {
+		slot := i + 1
+		quickSelectButton.MapCommand(entities.PushButtonEntityCommand, func() error { return c.denon.RecallQuickSelect(slot) })
+	}
 
-	// Media Player
-	c.denon.AddHandleEntityChangeFunc("MainZonePower", func(value interface{}) {
-		c.mediaPlayer.SetAttribute(entities.StateMediaPlayerEntityAttribute, c.mapOnState[c.denon.IsOn()])
-	})
+	mainZoneMediaPlayer := c.mediaPlayers[denonavr.MainZone]
 
-	c.denon.AddHandleEntityChangeFunc("MainZoneVolume", func(value interface{}) {
+	c.mapMediaPlayerCommand(mainZoneMediaPlayer, entities.MediaPlayerEntityCommand("OUTPUT_MONITOR1"), c.denon.SetMoni1Out)
+	c.mapMediaPlayerCommand(mainZoneMediaPlayer, entities.MediaPlayerEntityCommand("OUTPUT_MONITOR2"), c.denon.SetMoni2Out)
+	c.mapMediaPlayerCommand(mainZoneMediaPlayer, entities.MediaPlayerEntityCommand("OUTPUT_MONITORAUTO"), c.denon.SetMoniAutoOut)
 
-		var volume float64
-		if s, err := strconv.ParseFloat(value.(string), 64); err == nil {
-			volume = s
-		}
+	// Store the current input, volume and sound mode in a quick select slot
+	for slot := 1; slot <= denonavr.QUICK_SELECT_SLOTS; slot++ {
+		c.mapMediaPlayerCommand(mainZoneMediaPlayer, entities.MediaPlayerEntityCommand(fmt.Sprintf("QUICK_SELECT_%d_MEMORY", slot)), func() error { return c.denon.StoreQuickSelect(slot) })
+	}
 
-		c.mediaPlayer.SetAttribute(entities.VolumeMediaPlayerEntityAttribute, volume+80)
-	})
+	// Channel volume
+	c.mapMediaPlayerCommand(mainZoneMediaPlayer, entities.MediaPlayerEntityCommand("CENTER_UP"), func() error { return c.denon.ChannelVolumeUp(denonavr.DenonChannelCenter) })
+	c.mapMediaPlayerCommand(mainZoneMediaPlayer, entities.MediaPlayerEntityCommand("CENTER_DOWN"), func() error { return c.denon.ChannelVolumeDown(denonavr.DenonChannelCenter) })
+	c.mapMediaPlayerCommand(mainZoneMediaPlayer, entities.MediaPlayerEntityCommand("SUB_UP"), func() error { return c.denon.ChannelVolumeUp(denonavr.DenonChannelSubwoofer) })
+	c.mapMediaPlayerCommand(mainZoneMediaPlayer, entities.MediaPlayerEntityCommand("SUB_DOWN"), func() error { return c.denon.ChannelVolumeDown(denonavr.DenonChannelSubwoofer) })
+	c.mapMediaPlayerCommand(mainZoneMediaPlayer, entities.MediaPlayerEntityCommand("CHANNEL_LEVEL_RESET"), c.denon.ResetChannelVolumes)
+
+	// Tone control
+	c.mapMediaPlayerCommand(mainZoneMediaPlayer, entities.MediaPlayerEntityCommand("BASS_UP"), c.denon.BassUp)
+	c.mapMediaPlayerCommand(mainZoneMediaPlayer, entities.MediaPlayerEntityCommand("BASS_DOWN"), c.denon.BassDown)
+	c.mapMediaPlayerCommand(mainZoneMediaPlayer, entities.MediaPlayerEntityCommand("TREBLE_UP"), c.denon.TrebleUp)
+	c.mapMediaPlayerCommand(mainZoneMediaPlayer, entities.MediaPlayerEntityCommand("TREBLE_DOWN"), c.denon.TrebleDown)
+	c.mapMediaPlayerCommand(mainZoneMediaPlayer, entities.MediaPlayerEntityCommand("TONE_DEFEAT_ON"), func() error { return c.denon.SetToneControl(false) })
+	c.mapMediaPlayerCommand(mainZoneMediaPlayer, entities.MediaPlayerEntityCommand("TONE_DEFEAT_OFF"), func() error { return c.denon.SetToneControl(true) })
+
+	// Audyssey
+	for name, multEQ := range denonavr.MULTEQ_MAPPING {
+		command := "MULTEQ_" + strings.ReplaceAll(name, " ", "_")
+		c.mapMediaPlayerCommand(mainZoneMediaPlayer, entities.MediaPlayerEntityCommand(command), func() error { return c.denon.SetMultEQ(multEQ) })
+	}
+	c.mapMediaPlayerCommand(mainZoneMediaPlayer, entities.MediaPlayerEntityCommand("DYNAMIC_EQ_ON"), func() error { return c.denon.SetDynamicEQ(true) })
+	c.mapMediaPlayerCommand(mainZoneMediaPlayer, entities.MediaPlayerEntityCommand("DYNAMIC_EQ_OFF"), func() error { return c.denon.SetDynamicEQ(false) })
+	for _, level := range denonavr.REFERENCE_LEVELS {
+		referenceLevel, _ := strconv.Atoi(level)
+		c.mapMediaPlayerCommand(mainZoneMediaPlayer, entities.MediaPlayerEntityCommand("REFERENCE_LEVEL_"+level), func() error { return c.denon.SetReferenceLevel(referenceLevel) })
+	}
+	for name, dynamicVolume := range denonavr.DYNAMIC_VOLUME_MAPPING {
+		c.mapMediaPlayerCommand(mainZoneMediaPlayer, entities.MediaPlayerEntityCommand("DYNAMIC_VOLUME_"+name), func() error { return c.denon.SetDynamicVolume(dynamicVolume) })
+	}
+	c.mapMediaPlayerCommand(mainZoneMediaPlayer, entities.MediaPlayerEntityCommand("NIGHT_MODE"), c.denon.CycleDynamicVolume)
+
+	// Dialog and subwoofer level
+	c.mapMediaPlayerCommand(mainZoneMediaPlayer, entities.MediaPlayerEntityCommand("DIALOG_LEVEL_ON"), func() error { return c.denon.SetDialogLevelEnabled(true) })
+	c.mapMediaPlayerCommand(mainZoneMediaPlayer, entities.MediaPlayerEntityCommand("DIALOG_LEVEL_OFF"), func() error { return c.denon.SetDialogLevelEnabled(false) })
+	c.mapMediaPlayerCommand(mainZoneMediaPlayer, entities.MediaPlayerEntityCommand("DIALOG_LEVEL_UP"), c.denon.DialogLevelUp)
+	c.mapMediaPlayerCommand(mainZoneMediaPlayer, entities.MediaPlayerEntityCommand("DIALOG_LEVEL_DOWN"), c.denon.DialogLevelDown)
+	for name, dialogEnhancer := range denonavr.DIALOG_ENHANCER_MAPPING {
+		c.mapMediaPlayerCommand(mainZoneMediaPlayer, entities.MediaPlayerEntityCommand("DIALOG_ENHANCER_"+name), func() error { return c.denon.SetDialogEnhancer(dialogEnhancer) })
+	}
+	c.mapMediaPlayerCommand(mainZoneMediaPlayer, entities.MediaPlayerEntityCommand("SUBWOOFER_LEVEL_ON"), func() error { return c.denon.SetSubwooferLevelEnabled(true) })
+	c.mapMediaPlayerCommand(mainZoneMediaPlayer, entities.MediaPlayerEntityCommand("SUBWOOFER_LEVEL_OFF"), func() error { return c.denon.SetSubwooferLevelEnabled(false) })
+	c.mapMediaPlayerCommand(mainZoneMediaPlayer, entities.MediaPlayerEntityCommand("SUBWOOFER_LEVEL_UP"), c.denon.SubwooferLevelUp)
+	c.mapMediaPlayerCommand(mainZoneMediaPlayer, entities.MediaPlayerEntityCommand("SUBWOOFER_LEVEL_DOWN"), c.denon.SubwooferLevelDown)
+
+	// Dirac Live and speaker preset
+	for name, filter := range denonavr.DIRAC_FILTER_MAPPING {
+		c.mapMediaPlayerCommand(mainZoneMediaPlayer, entities.MediaPlayerEntityCommand("DIRAC_"+name), func() error { return c.denon.SetDiracFilter(filter) })
+	}
+	for preset := denonavr.SPEAKER_PRESET_MIN; preset <= denonavr.SPEAKER_PRESET_MAX; preset++ {
+		c.mapMediaPlayerCommand(mainZoneMediaPlayer, entities.MediaPlayerEntityCommand("SPEAKER_PRESET_"+strconv.Itoa(preset)), func() error { return c.denon.SetSpeakerPreset(preset) })
+	}
+
+	// Decoder settings
+	for name, drc := range denonavr.DYNAMIC_RANGE_COMPRESSION_MAPPING {
+		c.mapMediaPlayerCommand(mainZoneMediaPlayer, entities.MediaPlayerEntityCommand("DRC_"+name), func() error { return c.denon.SetDynamicRangeCompression(drc) })
+	}
+	c.mapMediaPlayerCommand(mainZoneMediaPlayer, entities.MediaPlayerEntityCommand("LFE_UP"), c.denon.LFELevelUp)
+	c.mapMediaPlayerCommand(mainZoneMediaPlayer, entities.MediaPlayerEntityCommand("LFE_DOWN"), c.denon.LFELevelDown)
+	c.mapMediaPlayerCommand(mainZoneMediaPlayer, entities.MediaPlayerEntityCommand("CINEMA_EQ_ON"), func() error { return c.denon.SetCinemaEQ(true) })
+	c.mapMediaPlayerCommand(mainZoneMediaPlayer, entities.MediaPlayerEntityCommand("CINEMA_EQ_OFF"), func() error { return c.denon.SetCinemaEQ(false) })
+	c.mapMediaPlayerCommand(mainZoneMediaPlayer, entities.MediaPlayerEntityCommand("LOUDNESS_MANAGEMENT_ON"), func() error { return c.denon.SetLoudnessManagement(true) })
+	c.mapMediaPlayerCommand(mainZoneMediaPlayer, entities.MediaPlayerEntityCommand("LOUDNESS_MANAGEMENT_OFF"), func() error { return c.denon.SetLoudnessManagement(false) })
+
+	// Upmixer options
+	for name, option := range denonavr.UPMIXER_OPTION_MAPPING {
+		c.mapMediaPlayerCommand(mainZoneMediaPlayer, entities.MediaPlayerEntityCommand(name+"_ON"), func() error { return c.denon.SetUpmixerOption(option, true) })
+		c.mapMediaPlayerCommand(mainZoneMediaPlayer, entities.MediaPlayerEntityCommand(name+"_OFF"), func() error { return c.denon.SetUpmixerOption(option, false) })
+	}
 
-	c.denon.AddHandleEntityChangeFunc("MainZoneMute", func(value interface{}) {
-		c.mediaPlayer.SetAttribute(entities.MutedMediaPlayeEntityAttribute, c.denon.MainZoneMuted())
+	c.dynamicEQSwitch.MapCommand(entities.OnSwitchEntityCommand, func() error { return c.denon.SetDynamicEQ(true) })
+	c.dynamicEQSwitch.MapCommand(entities.OffSwitchEntityCommand, func() error { return c.denon.SetDynamicEQ(false) })
+	c.dynamicEQSwitch.MapCommand(entities.ToggleSwitchEntityCommand, c.denon.ToggleDynamicEQ)
+	c.nightModeButton.MapCommand(entities.PushButtonEntityCommand, c.denon.CycleDynamicVolume)
+
+	c.denon.AddHandleEntityChangeFunc("DynamicEQ", func(value interface{}) {
+		c.dynamicEQSwitch.SetAttribute(entities.MediaPlayerEntityAttributes(entities.StateSwitchEntityyAttribute), entities.SwitchEntityState(value.(string)))
 	})
 
-	c.denon.AddHandleEntityChangeFunc("MainZoneInputFuncList", func(value interface{}) {
-		c.mediaPlayer.SetAttribute(entities.SourceListMediaPlayerEntityAttribute, value.([]string))
+	c.denon.AddHandleEntityChangeFunc("DynamicVolume", func(value interface{}) {
+		// Show the readable name of the setting, e.g. MEDIUM instead of MED
+		state := value.(string)
+		for name, dynamicVolume := range denonavr.DYNAMIC_VOLUME_MAPPING {
+			if string(dynamicVolume) == state {
+				state = name
+			}
+		}
+		c.dynamicVolumeSensor.SetAttributes(map[string]interface{}{
+			string(entities.StateSensorEntityyAttribute):  entities.OnSensorEntityState,
+			string(entities.ValueSensortEntityyAttribute): state,
+		})
 	})
 
-	c.denon.AddHandleEntityChangeFunc("MainZoneInputFuncSelect", func(value interface{}) {
-		c.mediaPlayer.SetAttribute(entities.SourceMediaPlayerEntityAttribute, value.(string))
+	// ECO mode
+	for _, ecoMode := range denonavr.ECO_MODES {
+		c.mapMediaPlayerCommand(mainZoneMediaPlayer, entities.MediaPlayerEntityCommand("ECO_"+string(ecoMode)), func() error { return c.denon.SetECOMode(ecoMode) })
+	}
+
+	// Tuner
+	c.mapMediaPlayerCommand(mainZoneMediaPlayer, entities.MediaPlayerEntityCommand("TUNER_BAND_AM"), func() error { return c.denon.SetTunerBand(denonavr.DenonTunerBandAM) })
+	c.mapMediaPlayerCommand(mainZoneMediaPlayer, entities.MediaPlayerEntityCommand("TUNER_BAND_FM"), func() error { return c.denon.SetTunerBand(denonavr.DenonTunerBandFM) })
+	c.mapMediaPlayerCommand(mainZoneMediaPlayer, entities.MediaPlayerEntityCommand("TUNER_FREQUENCY_UP"), c.denon.TunerFrequencyUp)
+	c.mapMediaPlayerCommand(mainZoneMediaPlayer, entities.MediaPlayerEntityCommand("TUNER_FREQUENCY_DOWN"), c.denon.TunerFrequencyDown)
+	c.mapMediaPlayerCommand(mainZoneMediaPlayer, entities.MediaPlayerEntityCommand("TUNER_PRESET_UP"), c.denon.TunerPresetUp)
+	c.mapMediaPlayerCommand(mainZoneMediaPlayer, entities.MediaPlayerEntityCommand("TUNER_PRESET_DOWN"), c.denon.TunerPresetDown)
+
+	// Dimmer
+	for name, dimmer := range denonavr.DIMMER_MAPPING {
+		c.mapMediaPlayerCommand(mainZoneMediaPlayer, entities.MediaPlayerEntityCommand("DIMMER_"+name), func() error { return c.denon.SetDimmer(dimmer) })
+	}
+	c.mapMediaPlayerCommand(mainZoneMediaPlayer, entities.MediaPlayerEntityCommand("DIMMER_CYCLE"), c.denon.CycleDimmer)
+
+	c.denon.AddHandleEntityChangeFunc("Dimmer", func(value interface{}) {
+		// Show the readable name of the setting, e.g. DARK instead of DAR
+		state := value.(string)
+		for name, dimmer := range denonavr.DIMMER_MAPPING {
+			if string(dimmer) == state {
+				state = name
+			}
+		}
+		c.dimmerSensor.SetAttributes(map[string]interface{}{
+			string(entities.StateSensorEntityyAttribute):  entities.OnSensorEntityState,
+			string(entities.ValueSensortEntityyAttribute): state,
+		})
 	})
 
-	c.denon.AddHandleEntityChangeFunc("MainZoneSurroundMode", func(value interface{}) {
-		c.mediaPlayer.SetAttribute(entities.SoundModeMediaPlayerEntityAttribute, value.(string))
+	// Enforce the ECO mode selected in the setup
+	if ecoMode := denonavr.DenonECOMode(c.IntegrationDriver.SetupData["ecomode"]); ecoMode != "" {
+		c.denon.AddHandleEntityChangeFunc("ECOMode", func(value interface{}) {
+			if denonavr.DenonECOMode(value.(string)) != ecoMode {
+				log.WithField("ecoMode", string(ecoMode)).Info("Set ECO mode selected in the setup")
+				if err := c.denon.SetECOMode(ecoMode); err != nil {
+					log.WithError(err).Error("Cannot set ECO mode")
+				}
+			}
+		})
+	}
+
+	// Show the telnet connection state as device state
+	c.denon.AddHandleEntityChangeFunc("TelnetConnectionState", func(value interface{}) {
+		switch value.(denonavr.TelnetConnectionState) {
+		case denonavr.TelnetConnectionStateConnected:
+			c.SetDeviceState(integration.ConnectedDeviceState)
+		case denonavr.TelnetConnectionStateConnecting, denonavr.TelnetConnectionStateReconnecting:
+			c.SetDeviceState(integration.ConnectingDeviceState)
+		}
 	})
 
-	// We can set the sound_mode_list without change handler. Its static
-	func() {
-		c.mediaPlayer.SetAttribute(entities.SoundModeListMediaPlayerEntityAttribute, c.denon.GetSoundModeList())
-	}()
+	// Media Player of each zone
+	for zone, mediaPlayer := range c.mediaPlayers {
+		c.configureMediaPlayer(zone, mediaPlayer)
+	}
 
 	// Media Title
 	c.denon.AddHandleEntityChangeFunc("media_title", func(value interface{}) {
-		c.mediaPlayer.SetAttribute(entities.MediaTitleMediaPlayerEntityAttribute, value.(string))
+		mainZoneMediaPlayer.SetAttribute(entities.MediaTitleMediaPlayerEntityAttribute, value.(string))
 	})
 
 	// Media Image URL
 	c.denon.AddHandleEntityChangeFunc("media_image_url", func(value interface{}) {
-		c.mediaPlayer.SetAttribute(entities.MediaImageUrlMediaPlayerEntityAttribute, value.(string))
+		mainZoneMediaPlayer.SetAttribute(entities.MediaImageUrlMediaPlayerEntityAttribute, value.(string))
 	})
 
-	// Add Commands
-	c.mediaPlayer.MapCommand(entities.OnMediaPlayerEntityCommand, c.denon.TurnOn)
-	c.mediaPlayer.MapCommand(entities.OffMediaPlayerEntityCommand, c.denon.TurnOff)
-	c.mediaPlayer.MapCommand(entities.ToggleMediaPlayerEntityCommand, c.denon.TogglePower)
-
-	c.mediaPlayer.AddCommand(entities.VolumeMediaPlayerEntityCommand, func(mediaPlayer entities.MediaPlayerEntity, params map[string]interface{}) int {
-		log.WithField("entityId", mediaPlayer.Id).Debug("VolumeMediaPlayerEntityCommand called")
-
-		var volume float64
-		if v, err := strconv.ParseFloat(params["volume"].(string), 64); err == nil {
-			volume = v
-		}
-		if err := c.denon.SetVolume(volume); err != nil {
-			return 404
-		}
-		return 200
-	})
-
-	// Volume commands
-	c.mediaPlayer.MapCommand(entities.VolumeUpMediaPlayerEntityCommand, c.denon.SetVolumeUp)
-	c.mediaPlayer.MapCommand(entities.VolumeDownMediaPlayerEntityCommand, c.denon.SetVolumeDown)
-	c.mediaPlayer.MapCommand(entities.MuteMediaPlayerEntityCommand, c.denon.MainZoneMute)
-	c.mediaPlayer.MapCommand(entities.UnmuteMediaPlayerEntityCommand, c.denon.MainZoneUnMute)
-	c.mediaPlayer.MapCommand(entities.MuteToggleMediaPlayerEntityCommand, c.denon.MainZoneMuteToggle)
+	// Decoder settings
+	for attribute, sensor := range c.decoderSettingSensors {
+		c.denon.AddHandleEntityChangeFunc(attribute, func(value interface{}) {
+			attributes := map[string]interface{}{
+				string(entities.StateSensorEntityyAttribute):  entities.OnSensorEntityState,
+				string(entities.ValueSensortEntityyAttribute): value.(string),
+			}
+			if attribute == "LFELevel" {
+				attributes[string(entities.UnitSSensorntityyAttribute)] = "dB"
+			}
+			sensor.SetAttributes(attributes)
+		})
+	}
 
-	// Source commands
-	c.mediaPlayer.AddCommand(entities.SelectSourcMediaPlayerEntityCommand, func(mediaPlayer entities.MediaPlayerEntity, params map[string]interface{}) int {
-		log.WithField("entityId", mediaPlayer.Id).Debug("SelectSourcMediaPlayerEntityCommand called")
-		if params["source"] != nil {
-			return c.denon.SetSelectSourceMainZone(params["source"].(string))
-		}
-		return 200
+	// The decoder settings are also shown on the main zone media player
+	c.denon.AddHandleEntityChangeFunc("DecoderSettings", func(value interface{}) {
+		mainZoneMediaPlayer.SetAttribute(entities.MediaAlbumMediaPlayerEntityAttribute, value.(string))
 	})
 
 	// Cursor commands
-	c.mediaPlayer.AddCommand(entities.CursorUpMediaPlayerEntityCommand, func(mediaPlayer entities.MediaPlayerEntity, params map[string]interface{}) int {
+	mainZoneMediaPlayer.AddCommand(entities.CursorUpMediaPlayerEntityCommand, func(mediaPlayer entities.MediaPlayerEntity, params map[string]interface{}) int {
 		log.WithField("entityId", mediaPlayer.Id).Debug("CursorUpMediaPlayerEntityCommand called")
-		return c.denon.CursorControl(denonavr.DenonCursorControlUp)
+		return c.denon.CursorControl(denonavr.DenonCursorControlUp).StatusCode()
 	})
-	c.mediaPlayer.AddCommand(entities.CursorDownMediaPlayerEntityCommand, func(mediaPlayer entities.MediaPlayerEntity, params map[string]interface{}) int {
+	mainZoneMediaPlayer.AddCommand(entities.CursorDownMediaPlayerEntityCommand, func(mediaPlayer entities.MediaPlayerEntity, params map[string]interface{}) int {
 		log.WithField("entityId", mediaPlayer.Id).Debug("CursorDownMediaPlayerEntityCommand called")
-		return c.denon.CursorControl(denonavr.DenonCursorControlDown)
+		return c.denon.CursorControl(denonavr.DenonCursorControlDown).StatusCode()
 	})
-	c.mediaPlayer.AddCommand(entities.CursorLeftMediaPlayerEntityCommand, func(mediaPlayer entities.MediaPlayerEntity, params map[string]interface{}) int {
+	mainZoneMediaPlayer.AddCommand(entities.CursorLeftMediaPlayerEntityCommand, func(mediaPlayer entities.MediaPlayerEntity, params map[string]interface{}) int {
 		log.WithField("entityId", mediaPlayer.Id).Debug("CursorUpMediaPlayerEntityCommand called")
-		return c.denon.CursorControl(denonavr.DenonCursorControlLeft)
+		return c.denon.CursorControl(denonavr.DenonCursorControlLeft).StatusCode()
 	})
-	c.mediaPlayer.AddCommand(entities.CursorRightMediaPlayerEntityCommand, func(mediaPlayer entities.MediaPlayerEntity, params map[string]interface{}) int {
+	mainZoneMediaPlayer.AddCommand(entities.CursorRightMediaPlayerEntityCommand, func(mediaPlayer entities.MediaPlayerEntity, params map[string]interface{}) int {
 		log.WithField("entityId", mediaPlayer.Id).Debug("CursorRightMediaPlayerEntityCommand called")
-		return c.denon.CursorControl(denonavr.DenonCursorControlRight)
+		return c.denon.CursorControl(denonavr.DenonCursorControlRight).StatusCode()
 	})
-	c.mediaPlayer.AddCommand(entities.CursorEnterMediaPlayerEntityCommand, func(mediaPlayer entities.MediaPlayerEntity, params map[string]interface{}) int {
+	mainZoneMediaPlayer.AddCommand(entities.CursorEnterMediaPlayerEntityCommand, func(mediaPlayer entities.MediaPlayerEntity, params map[string]interface{}) int {
 		log.WithField("entityId", mediaPlayer.Id).Debug("CursorEnterMediaPlayerEntityCommand called")
-		return c.denon.CursorControl(denonavr.DenonCursorControlEnter)
+		return c.denon.CursorControl(denonavr.DenonCursorControlEnter).StatusCode()
 	})
-	c.mediaPlayer.AddCommand(entities.BackMediaPlayerEntityCommand, func(mediaPlayer entities.MediaPlayerEntity, params map[string]interface{}) int {
+	mainZoneMediaPlayer.AddCommand(entities.BackMediaPlayerEntityCommand, func(mediaPlayer entities.MediaPlayerEntity, params map[string]interface{}) int {
 		log.WithField("entityId", mediaPlayer.Id).Debug("BackMediaPlayerEntityCommand called")
-		return c.denon.CursorControl(denonavr.DenonCursorControlReturn)
+		return c.denon.CursorControl(denonavr.DenonCursorControlReturn).StatusCode()
 	})
-	c.mediaPlayer.AddCommand(entities.MenuMediaPlayerEntityCommand, func(mediaPlayer entities.MediaPlayerEntity, params map[string]interface{}) int {
+	mainZoneMediaPlayer.AddCommand(entities.MenuMediaPlayerEntityCommand, func(mediaPlayer entities.MediaPlayerEntity, params map[string]interface{}) int {
 		log.WithField("entityId", mediaPlayer.Id).Debug("MenuMediaPlayerEntityCommand called")
-		return c.denon.CursorControl(denonavr.DenonCursorControlMenu)
+		return c.denon.CursorControl(denonavr.DenonCursorControlMenu).StatusCode()
 	})
-	c.mediaPlayer.AddCommand(entities.InfoMediaPlayerEntityCommand, func(mediaPlayer entities.MediaPlayerEntity, params map[string]interface{}) int {
+	mainZoneMediaPlayer.AddCommand(entities.InfoMediaPlayerEntityCommand, func(mediaPlayer entities.MediaPlayerEntity, params map[string]interface{}) int {
 		log.WithField("entityId", mediaPlayer.Id).Debug("InfoMediaPlayerEntityCommand called")
-		return c.denon.CursorControl(denonavr.DenonCursorControlMenuInfo)
+		return c.denon.CursorControl(denonavr.DenonCursorControlMenuInfo).StatusCode()
+	})
+
+}
+
+// Configure the entity change functions and commands of the media player of a zone
+func (c *DenonAVRClient) configureMediaPlayer(zone denonavr.DenonZone, mediaPlayer *entities.MediaPlayerEntity) {
+
+	zoneName := c.denon.GetZoneName(zone)
+
+	c.denon.AddHandleEntityChangeFunc(zoneName+"Power", func(value interface{}) {
+		mediaPlayer.SetAttribute(entities.StateMediaPlayerEntityAttribute, c.mapOnState[c.denon.IsOn(zone)])
+	})
+
+	c.denon.AddHandleEntityChangeFunc(zoneName+"Volume", func(value interface{}) {
+
+		var volume float64
+		if s, err := strconv.ParseFloat(value.(string), 64); err == nil {
+			volume = s
+		}
+
+		mediaPlayer.SetAttribute(entities.VolumeMediaPlayerEntityAttribute, volume+80)
+	})
+
+	c.denon.AddHandleEntityChangeFunc(zoneName+"Mute", func(value interface{}) {
+		mediaPlayer.SetAttribute(entities.MutedMediaPlayeEntityAttribute, c.denon.Muted(zone))
+	})
+
+	c.denon.AddHandleEntityChangeFunc(zoneName+"InputFuncList", func(value interface{}) {
+		mediaPlayer.SetAttribute(entities.SourceListMediaPlayerEntityAttribute, value.([]string))
+	})
+
+	c.denon.AddHandleEntityChangeFunc(zoneName+"InputFuncSelect", func(value interface{}) {
+		mediaPlayer.SetAttribute(entities.SourceMediaPlayerEntityAttribute, value.(string))
+	})
+
+	c.denon.AddHandleEntityChangeFunc(zoneName+"SurroundMode", func(value interface{}) {
+		mediaPlayer.SetAttribute(entities.SoundModeMediaPlayerEntityAttribute, value.(string))
+	})
+
+	// We can set the sound_mode_list without change handler. Its static
+	func() {
+		mediaPlayer.SetAttribute(entities.SoundModeListMediaPlayerEntityAttribute, c.denon.GetSoundModeList(zone))
+	}()
+
+	// Add Commands
+	c.mapMediaPlayerCommand(mediaPlayer, entities.OnMediaPlayerEntityCommand, func() error { return c.denon.TurnOn(zone) })
+	c.mapMediaPlayerCommand(mediaPlayer, entities.OffMediaPlayerEntityCommand, func() error { return c.denon.TurnOff(zone) })
+	c.mapMediaPlayerCommand(mediaPlayer, entities.ToggleMediaPlayerEntityCommand, func() error { return c.denon.TogglePower(zone) })
+
+	mediaPlayer.AddCommand(entities.VolumeMediaPlayerEntityCommand, func(mediaPlayer entities.MediaPlayerEntity, params map[string]interface{}) int {
+		log.WithField("entityId", mediaPlayer.Id).Debug("VolumeMediaPlayerEntityCommand called")
+
+		var volume float64
+		if v, err := strconv.ParseFloat(params["volume"].(string), 64); err == nil {
+			volume = v
+		}
+		return denonavr.GetCommandResult(c.denon.SetVolume(zone, volume)).StatusCode()
+	})
+
+	// Volume commands
+	c.mapMediaPlayerCommand(mediaPlayer, entities.VolumeUpMediaPlayerEntityCommand, func() error { return c.denon.SetVolumeUp(zone) })
+	c.mapMediaPlayerCommand(mediaPlayer, entities.VolumeDownMediaPlayerEntityCommand, func() error { return c.denon.SetVolumeDown(zone) })
+	c.mapMediaPlayerCommand(mediaPlayer, entities.MuteMediaPlayerEntityCommand, func() error { return c.denon.Mute(zone) })
+	c.mapMediaPlayerCommand(mediaPlayer, entities.UnmuteMediaPlayerEntityCommand, func() error { return c.denon.UnMute(zone) })
+	c.mapMediaPlayerCommand(mediaPlayer, entities.MuteToggleMediaPlayerEntityCommand, func() error { return c.denon.MuteToggle(zone) })
+
+	// Sleep timer
+	c.mapMediaPlayerCommand(mediaPlayer, entities.MediaPlayerEntityCommand("SLEEP_30"), func() error { return c.denon.SetSleepTimer(zone, 30) })
+	c.mapMediaPlayerCommand(mediaPlayer, entities.MediaPlayerEntityCommand("SLEEP_60"), func() error { return c.denon.SetSleepTimer(zone, 60) })
+	c.mapMediaPlayerCommand(mediaPlayer, entities.MediaPlayerEntityCommand("SLEEP_OFF"), func() error { return c.denon.SleepTimerOff(zone) })
+
+	// Source commands
+	mediaPlayer.AddCommand(entities.SelectSourcMediaPlayerEntityCommand, func(mediaPlayer entities.MediaPlayerEntity, params map[string]interface{}) int {
+		log.WithField("entityId", mediaPlayer.Id).Debug("SelectSourcMediaPlayerEntityCommand called")
+		if params["source"] != nil {
+			return c.denon.SetSelectSource(zone, params["source"].(string)).StatusCode()
+		}
+		return 200
 	})
 
 	// Sound Mode
-	c.mediaPlayer.AddCommand(entities.SelectSoundModeMediaPlayerEntityCommand, func(mediaPlayer entities.MediaPlayerEntity, params map[string]interface{}) int {
+	mediaPlayer.AddCommand(entities.SelectSoundModeMediaPlayerEntityCommand, func(mediaPlayer entities.MediaPlayerEntity, params map[string]interface{}) int {
 		log.WithField("entityId", mediaPlayer.Id).Debug("SelectSoundModeMediaPlayerEntityCommand called")
-		return c.denon.SetSoundModeMainZone(params["mode"].(string))
+		return c.denon.SetSoundMode(zone, params["mode"].(string)).StatusCode()
 	})
 
 }
 
+// Map a media player command to a function call
+// The status code reported to the remote depends on the result of the command
+func (c *DenonAVRClient) mapMediaPlayerCommand(mediaPlayer *entities.MediaPlayerEntity, command entities.MediaPlayerEntityCommand, f func() error) {
+
+	mediaPlayer.AddCommand(command, func(entity entities.MediaPlayerEntity, params map[string]interface{}) int {
+		return denonavr.GetCommandResult(f()).StatusCode()
+	})
+}
+
 func (c *DenonAVRClient) denonClientLoop() {
 	log.Debug("Start Denon Client Loop")
 
@@ -384,6 +954,9 @@ func (c *DenonAVRClient) denonClientLoop() {
 	// Start the Denon Liste Loop if already configured
 	if c.denon != nil {
 
+		// Entities and commands depending on the receiver
+		c.configureDeviceCapabilities()
+
 		// Configure Denon Client
 		c.configureDenon()
 
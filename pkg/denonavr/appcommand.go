@@ -0,0 +1,241 @@
+package denonavr
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type DenonAppCommand string
+
+const (
+	DenonAppCommandGetAllZonePowerStatus DenonAppCommand = "GetAllZonePowerStatus"
+	DenonAppCommandGetAllZoneVolume      DenonAppCommand = "GetAllZoneVolume"
+	DenonAppCommandGetAllZoneMuteStatus  DenonAppCommand = "GetAllZoneMuteStatus"
+	DenonAppCommandGetAllZoneSource      DenonAppCommand = "GetAllZoneSource"
+	DenonAppCommandGetSurroundModeStatus DenonAppCommand = "GetSurroundModeStatus"
+	DenonAppCommandGetToneControl        DenonAppCommand = "GetToneControl"
+)
+
+// Commands sent with each poll to get the state of all zones in one round trip
+var APPCOMMAND_STATUS_QUERIES = []DenonAppCommand{
+	DenonAppCommandGetAllZonePowerStatus,
+	DenonAppCommandGetAllZoneVolume,
+	DenonAppCommandGetAllZoneMuteStatus,
+	DenonAppCommandGetAllZoneSource,
+	DenonAppCommandGetSurroundModeStatus,
+	DenonAppCommandGetToneControl,
+}
+
+// Returned when the receiver does not support the AppCommand API
+var ErrAppCommandNotSupported = errors.New("AppCommand API not supported")
+
+// Returned when the receiver sent an error or a response that cannot be parsed
+var ErrAppCommandInvalidResponse = errors.New("invalid AppCommand response")
+
+// The status files are used after this many invalid responses in a row
+const APPCOMMAND_MAX_FAILURES int32 = 3
+
+type DenonAppCommandRequest struct {
+	XMLName  xml.Name                    `xml:"tx"`
+	Commands []DenonAppCommandRequestCmd `xml:"cmd"`
+}
+
+type DenonAppCommandRequestCmd struct {
+	Id   string          `xml:"id,attr"`
+	Name DenonAppCommand `xml:",chardata"`
+}
+
+// The results are returned in the order of the commands in the request
+type DenonAppCommandResponse struct {
+	XMLName xml.Name                `xml:"rx"`
+	Results []DenonAppCommandResult `xml:"cmd"`
+}
+
+// Result of a single command, only the fields returned by the command are set
+type DenonAppCommandResult struct {
+	Zone1       DenonAppCommandZoneResult `xml:"zone1"`
+	Zone2       DenonAppCommandZoneResult `xml:"zone2"`
+	Zone3       DenonAppCommandZoneResult `xml:"zone3"`
+	Surround    string                    `xml:"surround"`
+	Status      string                    `xml:"status"`
+	Adjust      string                    `xml:"adjust"`
+	BassLevel   string                    `xml:"basslevel"`
+	BassValue   string                    `xml:"bassvalue"`
+	TrebleLevel string                    `xml:"treblelevel"`
+	TrebleValue string                    `xml:"treblevalue"`
+}
+
+// Zone result of the GetAllZone commands
+// Power and mute status are the value of the zone element, volume and source are child elements
+type DenonAppCommandZoneResult struct {
+	Value  string `xml:",chardata"`
+	Volume string `xml:"volume"`
+	Source string `xml:"source"`
+}
+
+// Return the result of a zone
+func (r DenonAppCommandResult) Zone(zone DenonZone) DenonAppCommandZoneResult {
+
+	switch zone {
+	case Zone2:
+		return r.Zone2
+	case Zone3:
+		return r.Zone3
+	}
+
+	return r.Zone1
+}
+
+// Send several commands in one request to the AppCommand API
+// The results are returned in the order of the commands
+func (d *DenonAVR) SendAppCommands(commands ...DenonAppCommand) ([]DenonAppCommandResult, error) {
+
+	request := DenonAppCommandRequest{}
+	for _, command := range commands {
+		request.Commands = append(request.Commands, DenonAppCommandRequestCmd{Id: "1", Name: command})
+	}
+
+	body, err := xml.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := d.httpClient.Post(d.getURL(APPCOMMAND_URL), "text/xml", bytes.NewReader(append([]byte(xml.Header), body...)))
+	if err != nil {
+		log.WithError(err).Error("Failed to send AppCommand to Denon AVR")
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusForbidden:
+		return nil, fmt.Errorf("%w: status code %d", ErrAppCommandNotSupported, resp.StatusCode)
+	default:
+		return nil, fmt.Errorf("%w: status code %d", ErrAppCommandInvalidResponse, resp.StatusCode)
+	}
+
+	responseBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		log.WithError(err).Error("Cannot read response body")
+		return nil, err
+	}
+
+	response := DenonAppCommandResponse{}
+	if err := xml.Unmarshal(responseBody, &response); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrAppCommandInvalidResponse, err)
+	}
+
+	if len(response.Results) != len(commands) {
+		return nil, fmt.Errorf("%w: got %d results for %d commands", ErrAppCommandInvalidResponse, len(response.Results), len(commands))
+	}
+
+	return response.Results, nil
+}
+
+// Update the state of all zones with one AppCommand request
+// Falls back to the goform status files if the receiver does not support the AppCommand API
+// Invalid responses are retried with the next update, the status files are used in the meantime
+func (d *DenonAVR) updateAppCommandStatusAndNotify() {
+
+	results, err := d.SendAppCommands(APPCOMMAND_STATUS_QUERIES...)
+	switch {
+	case errors.Is(err, ErrAppCommandNotSupported):
+		log.WithError(err).Info("Use the status files to get the state of the receiver")
+		d.appCommandSupported.Store(false)
+		d.updateStatusFilesAndNotify()
+		return
+	case errors.Is(err, ErrAppCommandInvalidResponse):
+		if d.appCommandFailures.Add(1) >= APPCOMMAND_MAX_FAILURES {
+			log.WithError(err).Info("Too many invalid AppCommand responses, use the status files to get the state of the receiver")
+			d.appCommandSupported.Store(false)
+		} else {
+			log.WithError(err).Debug("Invalid AppCommand response, retry with the next update")
+		}
+		d.updateStatusFilesAndNotify()
+		return
+	case err != nil:
+		d.controlChannel <- "http_error"
+		return
+	}
+
+	d.appCommandFailures.Store(0)
+
+	power, volume, mute, source, surround, tone := results[0], results[1], results[2], results[3], results[4], results[5]
+
+	devicePower := "STANDBY"
+
+	for _, zone := range []DenonZone{MainZone, Zone2, Zone3} {
+
+		zoneName := d.GetZoneName(zone)
+
+		// Receivers without this zone return no value
+		if zonePower := strings.TrimSpace(power.Zone(zone).Value); zonePower != "" {
+			d.SetAttribute(zoneName+"Power", zonePower)
+			if zonePower == "ON" {
+				devicePower = "ON"
+			}
+		}
+
+		if zoneVolume := volume.Zone(zone).Volume; zoneVolume != "" && zoneVolume != "--" {
+			if v, err := strconv.ParseFloat(zoneVolume, 64); err == nil {
+				d.SetAttribute(zoneName+"Volume", fmt.Sprintf("%0.1f", v))
+			}
+		}
+
+		if zoneMute := strings.TrimSpace(mute.Zone(zone).Value); zoneMute != "" {
+			d.SetAttribute(zoneName+"Mute", strings.ToLower(zoneMute))
+		}
+
+		if zoneSource := source.Zone(zone).Source; zoneSource != "" {
+			if zone == MainZone {
+				// Not polled via the main zone data, but needed for the media title and image
+				d.setMainZoneInputFuncSelect(zoneSource)
+			}
+			d.SetAttribute(zoneName+"InputFuncSelect", d.getRenamedInputFuncSelect(zone, zoneSource))
+		}
+	}
+
+	d.SetAttribute("POWER", devicePower)
+
+	if surroundMode := strings.TrimSpace(surround.Surround); surroundMode != "" {
+		d.SetAttribute("MainZoneSurroundMode", d.getSurroundModeCategory(surroundMode))
+	}
+
+	if tone.Status != "" {
+		d.handleAppCommandToneControl(tone)
+	}
+
+	// Dirac and the speaker preset are only available with the AppCommand0300 API
+	if d.isAppCommand0300Available() {
+		d.updateAppCommand0300SettingsAndNotify()
+	}
+
+	d.updateMediaAndNotify()
+}
+
+// <status>1</status><basslevel>+2dB</basslevel><treblelevel>0dB</treblelevel>
+func (d *DenonAVR) handleAppCommandToneControl(tone DenonAppCommandResult) {
+
+	toneControl := "OFF"
+	if tone.Status == "1" {
+		toneControl = "ON"
+	}
+	d.SetAttribute("ToneControl", toneControl)
+
+	if bass, err := strconv.ParseFloat(strings.TrimSuffix(tone.BassLevel, "dB"), 64); err == nil {
+		d.SetAttribute("Bass", fmt.Sprintf("%0.1f", bass))
+	}
+
+	if treble, err := strconv.ParseFloat(strings.TrimSuffix(tone.TrebleLevel, "dB"), 64); err == nil {
+		d.SetAttribute("Treble", fmt.Sprintf("%0.1f", treble))
+	}
+}
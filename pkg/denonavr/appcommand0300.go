@@ -0,0 +1,532 @@
+package denonavr
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Commands of the AppCommand0300 API, each reads or writes a list of parameters
+type DenonAppCommand0300 string
+
+const (
+	DenonAppCommand0300GetAudyssey      DenonAppCommand0300 = "GetAudyssey"
+	DenonAppCommand0300SetAudyssey      DenonAppCommand0300 = "SetAudyssey"
+	DenonAppCommand0300GetDirac         DenonAppCommand0300 = "GetDirac"
+	DenonAppCommand0300SetDirac         DenonAppCommand0300 = "SetDirac"
+	DenonAppCommand0300GetSpeakerPreset DenonAppCommand0300 = "GetSpeakerPreset"
+	DenonAppCommand0300SetSpeakerPreset DenonAppCommand0300 = "SetSpeakerPreset"
+	DenonAppCommand0300GetSubwoofer     DenonAppCommand0300 = "GetSubwoofer"
+	DenonAppCommand0300SetSubwoofer     DenonAppCommand0300 = "SetSubwoofer"
+	DenonAppCommand0300GetSoundDetail   DenonAppCommand0300 = "GetSoundDetail"
+	DenonAppCommand0300SetSoundDetail   DenonAppCommand0300 = "SetSoundDetail"
+)
+
+// The AppCommand0300 API is not used for this long after a request failed, the setters send the PS commands meanwhile
+const APPCOMMAND0300_RETRY_INTERVAL = 30 * time.Second
+
+// Parameters of the GetAudyssey and SetAudyssey commands
+const (
+	APPCOMMAND0300_PARAM_MULTEQ          string = "multeq"
+	APPCOMMAND0300_PARAM_DYNAMICEQ       string = "dynamiceq"
+	APPCOMMAND0300_PARAM_REFERENCE_LEVEL string = "reflevoffset"
+	APPCOMMAND0300_PARAM_DYNAMIC_VOLUME  string = "dynamicvol"
+)
+
+// Parameters of the GetDirac, GetSpeakerPreset, GetSubwoofer and GetSoundDetail commands and their Set commands
+const (
+	APPCOMMAND0300_PARAM_DIRAC_FILTER           string = "filter"
+	APPCOMMAND0300_PARAM_SPEAKER_PRESET         string = "preset"
+	APPCOMMAND0300_PARAM_SUBWOOFER_LEVEL_ADJUST string = "subwooferadjust"
+	APPCOMMAND0300_PARAM_SUBWOOFER_LEVEL        string = "subwooferlevel"
+	APPCOMMAND0300_PARAM_DIALOG_ENHANCER        string = "dialogenhancer"
+	APPCOMMAND0300_PARAM_DIALOG_LEVEL_ADJUST    string = "dialogadjust"
+	APPCOMMAND0300_PARAM_DIALOG_LEVEL           string = "dialoglevel"
+)
+
+// Parameters read with each update of the advanced audio settings
+var APPCOMMAND0300_PARAMETERS = map[DenonAppCommand0300][]string{
+	DenonAppCommand0300GetAudyssey:      {APPCOMMAND0300_PARAM_MULTEQ, APPCOMMAND0300_PARAM_DYNAMICEQ, APPCOMMAND0300_PARAM_REFERENCE_LEVEL, APPCOMMAND0300_PARAM_DYNAMIC_VOLUME},
+	DenonAppCommand0300GetDirac:         {APPCOMMAND0300_PARAM_DIRAC_FILTER},
+	DenonAppCommand0300GetSpeakerPreset: {APPCOMMAND0300_PARAM_SPEAKER_PRESET},
+	DenonAppCommand0300GetSubwoofer:     {APPCOMMAND0300_PARAM_SUBWOOFER_LEVEL_ADJUST, APPCOMMAND0300_PARAM_SUBWOOFER_LEVEL},
+	DenonAppCommand0300GetSoundDetail:   {APPCOMMAND0300_PARAM_DIALOG_ENHANCER, APPCOMMAND0300_PARAM_DIALOG_LEVEL_ADJUST, APPCOMMAND0300_PARAM_DIALOG_LEVEL},
+}
+
+// The AppCommand0300 API uses indexes instead of the telnet values
+var APPCOMMAND0300_MULTEQ_MAPPING = map[string]DenonMultEQ{
+	"0": DenonMultEQOff,
+	"1": DenonMultEQFlat,
+	"2": DenonMultEQBypassLR,
+	"3": DenonMultEQAudyssey,
+	"4": DenonMultEQManual,
+}
+
+var APPCOMMAND0300_DYNAMIC_VOLUME_MAPPING = map[string]DenonDynamicVolume{
+	"0": DenonDynamicVolumeOff,
+	"1": DenonDynamicVolumeLight,
+	"2": DenonDynamicVolumeMedium,
+	"3": DenonDynamicVolumeHeavy,
+}
+
+var APPCOMMAND0300_DIRAC_FILTER_MAPPING = map[string]DenonDiracFilter{
+	"0": DenonDiracFilterOff,
+	"1": DenonDiracFilterSlot1,
+	"2": DenonDiracFilterSlot2,
+	"3": DenonDiracFilterSlot3,
+}
+
+var APPCOMMAND0300_DIALOG_ENHANCER_MAPPING = map[string]DenonDialogEnhancer{
+	"0": DenonDialogEnhancerOff,
+	"1": DenonDialogEnhancerLow,
+	"2": DenonDialogEnhancerMedium,
+	"3": DenonDialogEnhancerHigh,
+}
+
+type DenonAppCommand0300Request struct {
+	XMLName  xml.Name                        `xml:"tx"`
+	Commands []DenonAppCommand0300RequestCmd `xml:"cmd"`
+}
+
+type DenonAppCommand0300RequestCmd struct {
+	Id     string                     `xml:"id,attr"`
+	Name   DenonAppCommand0300        `xml:"name"`
+	Params []DenonAppCommand0300Param `xml:"list>param"`
+}
+
+type DenonAppCommand0300Response struct {
+	XMLName xml.Name                         `xml:"rx"`
+	Results []DenonAppCommand0300ResponseCmd `xml:"cmd"`
+}
+
+type DenonAppCommand0300ResponseCmd struct {
+	Name   DenonAppCommand0300        `xml:"name"`
+	Params []DenonAppCommand0300Param `xml:"list>param"`
+}
+
+// A parameter with its value, the control attribute tells if the parameter can be changed
+type DenonAppCommand0300Param struct {
+	Name    string `xml:"name,attr"`
+	Control string `xml:"control,attr,omitempty"`
+	Value   string `xml:",chardata"`
+}
+
+// Audyssey settings as read and written with the AppCommand0300 API
+type DenonAudysseySettings struct {
+	MultEQ         DenonMultEQ
+	DynamicEQ      bool
+	ReferenceLevel int
+	DynamicVolume  DenonDynamicVolume
+}
+
+// Subwoofer level adjustment as read and written with the AppCommand0300 API
+type DenonSubwooferSettings struct {
+	LevelAdjust bool
+	Level       float64
+}
+
+// Dialog settings as read and written with the AppCommand0300 API
+type DenonSoundDetailSettings struct {
+	DialogEnhancer    DenonDialogEnhancer
+	DialogLevelAdjust bool
+	DialogLevel       float64
+}
+
+// Send several commands in one request to the AppCommand0300 API
+// The results are returned in the order of the commands
+func (d *DenonAVR) sendAppCommands0300(commands ...DenonAppCommand0300RequestCmd) ([]DenonAppCommand0300ResponseCmd, error) {
+
+	request := DenonAppCommand0300Request{Commands: commands}
+
+	body, err := xml.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := d.httpClient.Post(d.getURL(APPCOMMAND0300_URL), "text/xml", bytes.NewReader(append([]byte(xml.Header), body...)))
+	if err != nil {
+		log.WithError(err).Error("Failed to send AppCommand0300 to Denon AVR")
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusForbidden:
+		return nil, fmt.Errorf("%w: status code %d", ErrAppCommandNotSupported, resp.StatusCode)
+	default:
+		return nil, fmt.Errorf("%w: status code %d", ErrAppCommandInvalidResponse, resp.StatusCode)
+	}
+
+	responseBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		log.WithError(err).Error("Cannot read response body")
+		return nil, err
+	}
+
+	response := DenonAppCommand0300Response{}
+	if err := xml.Unmarshal(responseBody, &response); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrAppCommandInvalidResponse, err)
+	}
+
+	if len(response.Results) != len(commands) {
+		return nil, fmt.Errorf("%w: got %d results for %d commands", ErrAppCommandInvalidResponse, len(response.Results), len(commands))
+	}
+
+	return response.Results, nil
+}
+
+func newAppCommand0300RequestCmd(command DenonAppCommand0300, params []DenonAppCommand0300Param) DenonAppCommand0300RequestCmd {
+	return DenonAppCommand0300RequestCmd{Id: "3", Name: command, Params: params}
+}
+
+// Return the parameters to read with a command
+func newAppCommand0300Params(names ...string) []DenonAppCommand0300Param {
+
+	var params []DenonAppCommand0300Param
+	for _, name := range names {
+		params = append(params, DenonAppCommand0300Param{Name: name})
+	}
+
+	return params
+}
+
+// Return the value of each parameter by its name
+func getAppCommand0300Values(params []DenonAppCommand0300Param) map[string]string {
+
+	values := make(map[string]string)
+	for _, param := range params {
+		values[param.Name] = strings.TrimSpace(param.Value)
+	}
+
+	return values
+}
+
+// Remember if the receiver supports the AppCommand0300 API
+// The API is probed with the first request and disabled on 403/404 or repeated invalid responses
+// After a failed request, e.g. a timeout, the API is not used for APPCOMMAND0300_RETRY_INTERVAL
+func (d *DenonAVR) handleAppCommand0300Result(err error) {
+
+	switch {
+	case err == nil:
+		d.appCommand0300Failures.Store(0)
+		d.appCommand0300RetryAt.Store(0)
+	case errors.Is(err, ErrAppCommandNotSupported):
+		log.WithError(err).Info("AppCommand0300 API not supported")
+		d.appCommand0300Supported.Store(false)
+	case errors.Is(err, ErrAppCommandInvalidResponse):
+		if d.appCommand0300Failures.Add(1) >= APPCOMMAND_MAX_FAILURES {
+			log.WithError(err).Info("Too many invalid AppCommand0300 responses, disable the AppCommand0300 API")
+			d.appCommand0300Supported.Store(false)
+		}
+	default:
+		log.WithError(err).Debugf("AppCommand0300 request failed, retry in %s", APPCOMMAND0300_RETRY_INTERVAL)
+		d.appCommand0300RetryAt.Store(time.Now().Add(APPCOMMAND0300_RETRY_INTERVAL).UnixNano())
+	}
+}
+
+// The receiver supports the AppCommand0300 API and the last request did not fail
+func (d *DenonAVR) isAppCommand0300Available() bool {
+	return d.appCommand0300Supported.Load() && time.Now().UnixNano() >= d.appCommand0300RetryAt.Load()
+}
+
+// Read parameters with the AppCommand0300 API
+// Returns the value of each parameter the receiver knows
+func (d *DenonAVR) GetAppCommand0300Parameters(command DenonAppCommand0300, names ...string) (map[string]string, error) {
+
+	results, err := d.sendAppCommands0300(newAppCommand0300RequestCmd(command, newAppCommand0300Params(names...)))
+	d.handleAppCommand0300Result(err)
+	if err != nil {
+		return nil, err
+	}
+
+	return getAppCommand0300Values(results[0].Params), nil
+}
+
+// Write parameters with the AppCommand0300 API
+func (d *DenonAVR) SetAppCommand0300Parameters(command DenonAppCommand0300, values map[string]string) error {
+
+	var params []DenonAppCommand0300Param
+	for name, value := range values {
+		params = append(params, DenonAppCommand0300Param{Name: name, Value: value})
+	}
+
+	_, err := d.sendAppCommands0300(newAppCommand0300RequestCmd(command, params))
+	d.handleAppCommand0300Result(err)
+
+	return err
+}
+
+// Write a parameter with the AppCommand0300 API if the receiver supports it and telnet is disabled
+// With telnet the command goes through the command queue and waits for its echo
+// Returns false if the parameter was not written, the caller sends the telnet command then
+func (d *DenonAVR) setAppCommand0300Parameter(command DenonAppCommand0300, name string, value string) bool {
+
+	if d.telnetEnabled || !d.isAppCommand0300Available() {
+		return false
+	}
+
+	if err := d.SetAppCommand0300Parameters(command, map[string]string{name: value}); err != nil {
+		log.WithError(err).WithField("param", name).Debug("Cannot set parameter via AppCommand0300, use the telnet command")
+		return false
+	}
+
+	return true
+}
+
+// Return the index of a value in a AppCommand0300 mapping
+func getAppCommand0300Index[T comparable](mapping map[string]T, value T) (string, bool) {
+
+	for index, v := range mapping {
+		if v == value {
+			return index, true
+		}
+	}
+
+	return "", false
+}
+
+func formatAppCommand0300Bool(enabled bool) string {
+
+	if enabled {
+		return "1"
+	}
+
+	return "0"
+}
+
+func formatAppCommand0300Level(level float64) string {
+	return fmt.Sprintf("%0.1f", level)
+}
+
+func parseAppCommand0300Level(value string) (float64, error) {
+	return strconv.ParseFloat(strings.TrimSuffix(value, "dB"), 64)
+}
+
+func parseAudysseySettings(values map[string]string) DenonAudysseySettings {
+
+	settings := DenonAudysseySettings{
+		MultEQ:        APPCOMMAND0300_MULTEQ_MAPPING[values[APPCOMMAND0300_PARAM_MULTEQ]],
+		DynamicEQ:     values[APPCOMMAND0300_PARAM_DYNAMICEQ] == "1",
+		DynamicVolume: APPCOMMAND0300_DYNAMIC_VOLUME_MAPPING[values[APPCOMMAND0300_PARAM_DYNAMIC_VOLUME]],
+	}
+
+	// The reference level offset is sent as index of 0, 5, 10 and 15dB
+	if index, err := strconv.Atoi(values[APPCOMMAND0300_PARAM_REFERENCE_LEVEL]); err == nil && index >= 0 && index < len(REFERENCE_LEVELS) {
+		settings.ReferenceLevel, _ = strconv.Atoi(REFERENCE_LEVELS[index])
+	}
+
+	return settings
+}
+
+// Return the index of a reference level offset as used by the AppCommand0300 API
+func getAppCommand0300ReferenceLevel(level int) (string, bool) {
+
+	for index, l := range REFERENCE_LEVELS {
+		if l == strconv.Itoa(level) {
+			return strconv.Itoa(index), true
+		}
+	}
+
+	return "", false
+}
+
+// Read the Audyssey settings with the AppCommand0300 API
+// Works without telnet, as the settings are not part of the status files
+func (d *DenonAVR) GetAudysseySettings() (*DenonAudysseySettings, error) {
+
+	values, err := d.GetAppCommand0300Parameters(DenonAppCommand0300GetAudyssey, APPCOMMAND0300_PARAMETERS[DenonAppCommand0300GetAudyssey]...)
+	if err != nil {
+		return nil, err
+	}
+
+	settings := parseAudysseySettings(values)
+
+	return &settings, nil
+}
+
+// Write the Audyssey settings with the AppCommand0300 API
+func (d *DenonAVR) SetAudysseySettings(settings DenonAudysseySettings) error {
+
+	values := make(map[string]string)
+
+	if index, ok := getAppCommand0300Index(APPCOMMAND0300_MULTEQ_MAPPING, settings.MultEQ); ok {
+		values[APPCOMMAND0300_PARAM_MULTEQ] = index
+	}
+
+	values[APPCOMMAND0300_PARAM_DYNAMICEQ] = formatAppCommand0300Bool(settings.DynamicEQ)
+
+	if index, ok := getAppCommand0300ReferenceLevel(settings.ReferenceLevel); ok {
+		values[APPCOMMAND0300_PARAM_REFERENCE_LEVEL] = index
+	}
+
+	if index, ok := getAppCommand0300Index(APPCOMMAND0300_DYNAMIC_VOLUME_MAPPING, settings.DynamicVolume); ok {
+		values[APPCOMMAND0300_PARAM_DYNAMIC_VOLUME] = index
+	}
+
+	if len(values) != 4 {
+		return fmt.Errorf("invalid Audyssey settings %+v", settings)
+	}
+
+	return d.SetAppCommand0300Parameters(DenonAppCommand0300SetAudyssey, values)
+}
+
+func parseSubwooferSettings(values map[string]string) DenonSubwooferSettings {
+
+	settings := DenonSubwooferSettings{
+		LevelAdjust: values[APPCOMMAND0300_PARAM_SUBWOOFER_LEVEL_ADJUST] == "1",
+	}
+	settings.Level, _ = parseAppCommand0300Level(values[APPCOMMAND0300_PARAM_SUBWOOFER_LEVEL])
+
+	return settings
+}
+
+// Read the subwoofer level adjustment with the AppCommand0300 API
+func (d *DenonAVR) GetSubwooferSettings() (*DenonSubwooferSettings, error) {
+
+	values, err := d.GetAppCommand0300Parameters(DenonAppCommand0300GetSubwoofer, APPCOMMAND0300_PARAMETERS[DenonAppCommand0300GetSubwoofer]...)
+	if err != nil {
+		return nil, err
+	}
+
+	settings := parseSubwooferSettings(values)
+
+	return &settings, nil
+}
+
+// Write the subwoofer level adjustment with the AppCommand0300 API
+func (d *DenonAVR) SetSubwooferSettings(settings DenonSubwooferSettings) error {
+
+	if settings.Level < SUBWOOFER_LEVEL_MIN || settings.Level > SUBWOOFER_LEVEL_MAX || !isLevelStep(settings.Level, SUBWOOFER_LEVEL_STEP) {
+		return fmt.Errorf("invalid subwoofer settings %+v", settings)
+	}
+
+	return d.SetAppCommand0300Parameters(DenonAppCommand0300SetSubwoofer, map[string]string{
+		APPCOMMAND0300_PARAM_SUBWOOFER_LEVEL_ADJUST: formatAppCommand0300Bool(settings.LevelAdjust),
+		APPCOMMAND0300_PARAM_SUBWOOFER_LEVEL:        formatAppCommand0300Level(settings.Level),
+	})
+}
+
+func parseSoundDetailSettings(values map[string]string) DenonSoundDetailSettings {
+
+	settings := DenonSoundDetailSettings{
+		DialogEnhancer:    APPCOMMAND0300_DIALOG_ENHANCER_MAPPING[values[APPCOMMAND0300_PARAM_DIALOG_ENHANCER]],
+		DialogLevelAdjust: values[APPCOMMAND0300_PARAM_DIALOG_LEVEL_ADJUST] == "1",
+	}
+	settings.DialogLevel, _ = parseAppCommand0300Level(values[APPCOMMAND0300_PARAM_DIALOG_LEVEL])
+
+	return settings
+}
+
+// Read the dialog settings with the AppCommand0300 API
+func (d *DenonAVR) GetSoundDetailSettings() (*DenonSoundDetailSettings, error) {
+
+	values, err := d.GetAppCommand0300Parameters(DenonAppCommand0300GetSoundDetail, APPCOMMAND0300_PARAMETERS[DenonAppCommand0300GetSoundDetail]...)
+	if err != nil {
+		return nil, err
+	}
+
+	settings := parseSoundDetailSettings(values)
+
+	return &settings, nil
+}
+
+// Write the dialog settings with the AppCommand0300 API
+func (d *DenonAVR) SetSoundDetailSettings(settings DenonSoundDetailSettings) error {
+
+	dialogEnhancer, ok := getAppCommand0300Index(APPCOMMAND0300_DIALOG_ENHANCER_MAPPING, settings.DialogEnhancer)
+	if !ok || settings.DialogLevel < DIALOG_LEVEL_MIN || settings.DialogLevel > DIALOG_LEVEL_MAX || !isLevelStep(settings.DialogLevel, DIALOG_LEVEL_STEP) {
+		return fmt.Errorf("invalid sound detail settings %+v", settings)
+	}
+
+	return d.SetAppCommand0300Parameters(DenonAppCommand0300SetSoundDetail, map[string]string{
+		APPCOMMAND0300_PARAM_DIALOG_ENHANCER:     dialogEnhancer,
+		APPCOMMAND0300_PARAM_DIALOG_LEVEL_ADJUST: formatAppCommand0300Bool(settings.DialogLevelAdjust),
+		APPCOMMAND0300_PARAM_DIALOG_LEVEL:        formatAppCommand0300Level(settings.DialogLevel),
+	})
+}
+
+// Update the advanced audio settings with one AppCommand0300 request
+// The Audyssey, subwoofer and dialog settings are updated from the telnet events if telnet is enabled
+func (d *DenonAVR) updateAppCommand0300SettingsAndNotify() {
+
+	commands := []DenonAppCommand0300{DenonAppCommand0300GetDirac, DenonAppCommand0300GetSpeakerPreset}
+	if !d.telnetEnabled {
+		commands = append(commands, DenonAppCommand0300GetAudyssey, DenonAppCommand0300GetSubwoofer, DenonAppCommand0300GetSoundDetail)
+	}
+
+	var requests []DenonAppCommand0300RequestCmd
+	for _, command := range commands {
+		requests = append(requests, newAppCommand0300RequestCmd(command, newAppCommand0300Params(APPCOMMAND0300_PARAMETERS[command]...)))
+	}
+
+	results, err := d.sendAppCommands0300(requests...)
+	d.handleAppCommand0300Result(err)
+	if err != nil {
+		return
+	}
+
+	for i, result := range results {
+		values := getAppCommand0300Values(result.Params)
+		// Receivers without the setting return no parameters
+		if len(values) == 0 {
+			continue
+		}
+
+		switch commands[i] {
+		case DenonAppCommand0300GetDirac:
+			if filter, ok := APPCOMMAND0300_DIRAC_FILTER_MAPPING[values[APPCOMMAND0300_PARAM_DIRAC_FILTER]]; ok {
+				d.SetAttribute("DiracFilter", string(filter))
+			}
+		case DenonAppCommand0300GetSpeakerPreset:
+			if preset, err := strconv.Atoi(values[APPCOMMAND0300_PARAM_SPEAKER_PRESET]); err == nil {
+				d.SetAttribute("SpeakerPreset", preset)
+			}
+		case DenonAppCommand0300GetAudyssey:
+			d.notifyAudysseySettings(parseAudysseySettings(values))
+		case DenonAppCommand0300GetSubwoofer:
+			settings := parseSubwooferSettings(values)
+			d.SetAttribute("SubwooferLevelState", formatOnOff(settings.LevelAdjust))
+			d.SetAttribute("SubwooferLevel", fmt.Sprintf("%0.1f", settings.Level))
+		case DenonAppCommand0300GetSoundDetail:
+			settings := parseSoundDetailSettings(values)
+			if settings.DialogEnhancer != "" {
+				d.SetAttribute("DialogEnhancer", string(settings.DialogEnhancer))
+			}
+			d.SetAttribute("DialogLevelState", formatOnOff(settings.DialogLevelAdjust))
+			d.SetAttribute("DialogLevel", fmt.Sprintf("%0.1f", settings.DialogLevel))
+		}
+	}
+}
+
+func (d *DenonAVR) notifyAudysseySettings(settings DenonAudysseySettings) {
+
+	if settings.MultEQ != "" {
+		d.SetAttribute("MultEQ", string(settings.MultEQ))
+	}
+
+	d.SetAttribute("DynamicEQ", formatOnOff(settings.DynamicEQ))
+
+	d.SetAttribute("ReferenceLevel", strconv.Itoa(settings.ReferenceLevel))
+
+	if settings.DynamicVolume != "" {
+		d.SetAttribute("DynamicVolume", string(settings.DynamicVolume))
+	}
+}
+
+func formatOnOff(enabled bool) string {
+
+	if enabled {
+		return "ON"
+	}
+
+	return "OFF"
+}
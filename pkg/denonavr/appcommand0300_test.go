@@ -0,0 +1,97 @@
+package denonavr
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+// Answer each AppCommand0300 request with one empty result and count the requests
+func newAppCommand0300Server(requests *atomic.Int32) *httptest.Server {
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != APPCOMMAND0300_URL {
+			http.NotFound(w, r)
+			return
+		}
+		requests.Add(1)
+		w.Write([]byte(`<?xml version="1.0" encoding="utf-8" ?><rx><cmd><name>SetAudyssey</name><list></list></cmd></rx>`))
+	}))
+}
+
+func TestSetAppCommand0300Parameter(t *testing.T) {
+
+	tests := []struct {
+		name          string
+		telnetEnabled bool
+		closed        bool
+		want          bool
+		wantRequests  int32
+		wantAvailable bool
+	}{
+		{"http", false, false, true, 1, true},
+		{"telnet", true, false, false, 0, true},
+		{"transport error", false, true, false, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			var requests atomic.Int32
+			server := newAppCommand0300Server(&requests)
+			defer server.Close()
+
+			d := NewDenonAVR(server.Listener.Addr().String(), tt.telnetEnabled, false)
+			d.BaseURL = server.URL
+			if tt.closed {
+				server.Close()
+			}
+
+			if got := d.setAppCommand0300Parameter(DenonAppCommand0300SetAudyssey, APPCOMMAND0300_PARAM_DYNAMICEQ, "1"); got != tt.want {
+				t.Errorf("setAppCommand0300Parameter() = %v, want %v", got, tt.want)
+			}
+			if got := requests.Load(); got != tt.wantRequests {
+				t.Errorf("%d AppCommand0300 requests, want %d", got, tt.wantRequests)
+			}
+			if got := d.isAppCommand0300Available(); got != tt.wantAvailable {
+				t.Errorf("isAppCommand0300Available() = %v, want %v", got, tt.wantAvailable)
+			}
+			// A failed request does not disable the API for good
+			if !d.appCommand0300Supported.Load() {
+				t.Error("AppCommand0300 API disabled")
+			}
+		})
+	}
+}
+
+// The setters do not wait for the timeout again after a failed request
+func TestSetAppCommand0300ParameterAfterTransportError(t *testing.T) {
+
+	var requests atomic.Int32
+	server := newAppCommand0300Server(&requests)
+
+	d := NewDenonAVR(server.Listener.Addr().String(), false, false)
+	d.BaseURL = server.URL
+	server.Close()
+
+	d.setAppCommand0300Parameter(DenonAppCommand0300SetAudyssey, APPCOMMAND0300_PARAM_DYNAMICEQ, "1")
+
+	// The receiver is reachable again, the API is used after the retry interval
+	server = newAppCommand0300Server(&requests)
+	defer server.Close()
+	d.BaseURL = server.URL
+
+	if d.setAppCommand0300Parameter(DenonAppCommand0300SetAudyssey, APPCOMMAND0300_PARAM_DYNAMICEQ, "1") {
+		t.Error("setAppCommand0300Parameter() used the AppCommand0300 API before the retry interval")
+	}
+	if got := requests.Load(); got != 0 {
+		t.Errorf("%d AppCommand0300 requests, want 0", got)
+	}
+
+	d.appCommand0300RetryAt.Store(0)
+
+	if !d.setAppCommand0300Parameter(DenonAppCommand0300SetAudyssey, APPCOMMAND0300_PARAM_DYNAMICEQ, "1") {
+		t.Error("setAppCommand0300Parameter() did not use the AppCommand0300 API after the retry interval")
+	}
+}
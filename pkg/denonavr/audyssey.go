@@ -0,0 +1,189 @@
+package denonavr
+
+import (
+	"fmt"
+	"strconv"
+
+	"k8s.io/utils/strings/slices"
+)
+
+type DenonMultEQ string
+type DenonDynamicVolume string
+
+const (
+	DenonMultEQAudyssey DenonMultEQ = "AUDYSSEY"
+	DenonMultEQBypassLR DenonMultEQ = "BYP.LR"
+	DenonMultEQFlat     DenonMultEQ = "FLAT"
+	DenonMultEQManual   DenonMultEQ = "MANUAL"
+	DenonMultEQOff      DenonMultEQ = "OFF"
+)
+
+const (
+	DenonDynamicVolumeOff    DenonDynamicVolume = "OFF"
+	DenonDynamicVolumeLight  DenonDynamicVolume = "LIT"
+	DenonDynamicVolumeMedium DenonDynamicVolume = "MED"
+	DenonDynamicVolumeHeavy  DenonDynamicVolume = "HEV"
+)
+
+var MULTEQ_MAPPING = map[string]DenonMultEQ{
+	"AUDYSSEY":  DenonMultEQAudyssey,
+	"BYPASS LR": DenonMultEQBypassLR,
+	"FLAT":      DenonMultEQFlat,
+	"MANUAL":    DenonMultEQManual,
+	"OFF":       DenonMultEQOff,
+}
+
+var DYNAMIC_VOLUME_MAPPING = map[string]DenonDynamicVolume{
+	"OFF":    DenonDynamicVolumeOff,
+	"LIGHT":  DenonDynamicVolumeLight,
+	"MEDIUM": DenonDynamicVolumeMedium,
+	"HEAVY":  DenonDynamicVolumeHeavy,
+}
+
+// Order used to cycle through the dynamic volume settings
+var DYNAMIC_VOLUME_CYCLE = []DenonDynamicVolume{
+	DenonDynamicVolumeOff,
+	DenonDynamicVolumeLight,
+	DenonDynamicVolumeMedium,
+	DenonDynamicVolumeHeavy,
+}
+
+var REFERENCE_LEVELS = []string{"0", "5", "10", "15"}
+
+func (d *DenonAVR) GetMultEQ() (DenonMultEQ, error) {
+
+	multEQ, err := d.GetAttribute("MultEQ")
+	if err != nil {
+		return "", err
+	}
+
+	return DenonMultEQ(multEQ.(string)), nil
+}
+
+// PSMULTEQ:AUDYSSEY
+func (d *DenonAVR) SetMultEQ(multEQ DenonMultEQ) error {
+
+	for _, m := range MULTEQ_MAPPING {
+		if m == multEQ {
+			if index, ok := getAppCommand0300Index(APPCOMMAND0300_MULTEQ_MAPPING, multEQ); ok && d.setAppCommand0300Parameter(DenonAppCommand0300SetAudyssey, APPCOMMAND0300_PARAM_MULTEQ, index) {
+				d.SetAttribute("MultEQ", string(multEQ))
+				return nil
+			}
+			_, err := d.sendCommandToDevice(DenonCommandPS, "MULTEQ:"+string(multEQ))
+			return err
+		}
+	}
+
+	return fmt.Errorf("invalid MultEQ setting %s", multEQ)
+}
+
+func (d *DenonAVR) GetDynamicEQ() (bool, error) {
+
+	dynamicEQ, err := d.GetAttribute("DynamicEQ")
+	if err != nil {
+		return false, err
+	}
+
+	return dynamicEQ.(string) == "ON", nil
+}
+
+// PSDYNEQ ON
+func (d *DenonAVR) SetDynamicEQ(enabled bool) error {
+
+	state := "OFF"
+	if enabled {
+		state = "ON"
+	}
+
+	if d.setAppCommand0300Parameter(DenonAppCommand0300SetAudyssey, APPCOMMAND0300_PARAM_DYNAMICEQ, formatAppCommand0300Bool(enabled)) {
+		d.SetAttribute("DynamicEQ", state)
+		return nil
+	}
+
+	_, err := d.sendCommandToDevice(DenonCommandPS, "DYNEQ "+state)
+	return err
+}
+
+func (d *DenonAVR) ToggleDynamicEQ() error {
+
+	enabled, err := d.GetDynamicEQ()
+	if err != nil {
+		return err
+	}
+
+	return d.SetDynamicEQ(!enabled)
+}
+
+// Return the reference level offset of Dynamic EQ in dB
+func (d *DenonAVR) GetReferenceLevel() (int, error) {
+
+	referenceLevel, err := d.GetAttribute("ReferenceLevel")
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.Atoi(referenceLevel.(string))
+}
+
+// Set the reference level offset of Dynamic EQ to 0, 5, 10 or 15dB
+// PSREFLEV 10
+func (d *DenonAVR) SetReferenceLevel(level int) error {
+
+	if !slices.Contains(REFERENCE_LEVELS, strconv.Itoa(level)) {
+		return fmt.Errorf("invalid reference level %d", level)
+	}
+
+	if index, ok := getAppCommand0300ReferenceLevel(level); ok && d.setAppCommand0300Parameter(DenonAppCommand0300SetAudyssey, APPCOMMAND0300_PARAM_REFERENCE_LEVEL, index) {
+		d.SetAttribute("ReferenceLevel", strconv.Itoa(level))
+		return nil
+	}
+
+	_, err := d.sendCommandToDevice(DenonCommandPS, "REFLEV "+strconv.Itoa(level))
+	return err
+}
+
+func (d *DenonAVR) GetDynamicVolume() (DenonDynamicVolume, error) {
+
+	dynamicVolume, err := d.GetAttribute("DynamicVolume")
+	if err != nil {
+		return "", err
+	}
+
+	return DenonDynamicVolume(dynamicVolume.(string)), nil
+}
+
+// PSDYNVOL MED
+func (d *DenonAVR) SetDynamicVolume(dynamicVolume DenonDynamicVolume) error {
+
+	for _, v := range DYNAMIC_VOLUME_CYCLE {
+		if v == dynamicVolume {
+			if index, ok := getAppCommand0300Index(APPCOMMAND0300_DYNAMIC_VOLUME_MAPPING, dynamicVolume); ok && d.setAppCommand0300Parameter(DenonAppCommand0300SetAudyssey, APPCOMMAND0300_PARAM_DYNAMIC_VOLUME, index) {
+				d.SetAttribute("DynamicVolume", string(dynamicVolume))
+				return nil
+			}
+			_, err := d.sendCommandToDevice(DenonCommandPS, "DYNVOL "+string(dynamicVolume))
+			return err
+		}
+	}
+
+	return fmt.Errorf("invalid dynamic volume setting %s", dynamicVolume)
+}
+
+// Switch to the next dynamic volume setting, OFF -> LIT -> MED -> HEV -> OFF
+func (d *DenonAVR) CycleDynamicVolume() error {
+
+	current, err := d.GetDynamicVolume()
+	if err != nil {
+		current = DenonDynamicVolumeHeavy
+	}
+
+	next := DenonDynamicVolumeOff
+	for i, v := range DYNAMIC_VOLUME_CYCLE {
+		if v == current {
+			next = DYNAMIC_VOLUME_CYCLE[(i+1)%len(DYNAMIC_VOLUME_CYCLE)]
+			break
+		}
+	}
+
+	return d.SetDynamicVolume(next)
+}
@@ -0,0 +1,114 @@
+package denonavr
+
+import (
+	"fmt"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type DenonChannel string
+
+const (
+	DenonChannelFrontLeft           DenonChannel = "FL"
+	DenonChannelFrontRight          DenonChannel = "FR"
+	DenonChannelCenter              DenonChannel = "C"
+	DenonChannelSubwoofer           DenonChannel = "SW"
+	DenonChannelSubwoofer2          DenonChannel = "SW2"
+	DenonChannelSurroundLeft        DenonChannel = "SL"
+	DenonChannelSurroundRight       DenonChannel = "SR"
+	DenonChannelSurroundBackLeft    DenonChannel = "SBL"
+	DenonChannelSurroundBackRight   DenonChannel = "SBR"
+	DenonChannelSurroundBack        DenonChannel = "SB"
+	DenonChannelFrontHeightLeft     DenonChannel = "FHL"
+	DenonChannelFrontHeightRight    DenonChannel = "FHR"
+	DenonChannelFrontWideLeft       DenonChannel = "FWL"
+	DenonChannelFrontWideRight      DenonChannel = "FWR"
+	DenonChannelTopFrontLeft        DenonChannel = "TFL"
+	DenonChannelTopFrontRight       DenonChannel = "TFR"
+	DenonChannelTopMiddleLeft       DenonChannel = "TML"
+	DenonChannelTopMiddleRight      DenonChannel = "TMR"
+	DenonChannelTopRearLeft         DenonChannel = "TRL"
+	DenonChannelTopRearRight        DenonChannel = "TRR"
+	DenonChannelRearHeightLeft      DenonChannel = "RHL"
+	DenonChannelRearHeightRight     DenonChannel = "RHR"
+	DenonChannelFrontDolbyLeft      DenonChannel = "FDL"
+	DenonChannelFrontDolbyRight     DenonChannel = "FDR"
+	DenonChannelSurroundDolbyLeft   DenonChannel = "SDL"
+	DenonChannelSurroundDolbyRight  DenonChannel = "SDR"
+	DenonChannelBackDolbyLeft       DenonChannel = "BDL"
+	DenonChannelBackDolbyRight      DenonChannel = "BDR"
+	DenonChannelSurroundHeightLeft  DenonChannel = "SHL"
+	DenonChannelSurroundHeightRight DenonChannel = "SHR"
+	DenonChannelTopSurround         DenonChannel = "TS"
+)
+
+// The channel volume is sent relative to 50 (0dB) and can be set from -12dB to +12dB in 0.5dB steps
+const (
+	CHANNEL_VOLUME_OFFSET float64 = 50
+	CHANNEL_VOLUME_MIN    float64 = -12
+	CHANNEL_VOLUME_MAX    float64 = 12
+	CHANNEL_VOLUME_STEP   float64 = 0.5
+)
+
+// Handle the channel volume telnet events
+// The device sends one event per channel followed by CVEND
+func (d *DenonAVR) handleChannelVolumeTelnetEvent(channel DenonChannel, param string) {
+
+	if channel == "END" || channel == "" {
+		return
+	}
+
+	level, err := parseTelnetLevel(param, CHANNEL_VOLUME_OFFSET)
+	if err != nil {
+		log.WithError(err).WithField("channel", channel).Error("failed to parse channel volume")
+		return
+	}
+
+	d.SetAttribute("ChannelVolume"+string(channel), fmt.Sprintf("%0.1f", level))
+}
+
+// Return the channel volume in dB
+func (d *DenonAVR) GetChannelVolume(channel DenonChannel) (float64, error) {
+
+	channelVolume, err := d.GetAttribute("ChannelVolume" + string(channel))
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseFloat(channelVolume.(string), 64)
+}
+
+// Set the channel volume in dB, e.g. CVC 535 for +3.5dB on the center
+func (d *DenonAVR) SetChannelVolume(channel DenonChannel, level float64) error {
+
+	if level < CHANNEL_VOLUME_MIN || level > CHANNEL_VOLUME_MAX {
+		return fmt.Errorf("channel volume %0.1f out of range", level)
+	}
+
+	if !isLevelStep(level, CHANNEL_VOLUME_STEP) {
+		return fmt.Errorf("channel volume %0.1f is not a multiple of %0.1fdB", level, CHANNEL_VOLUME_STEP)
+	}
+
+	_, err := d.sendCommandToDevice(DenonCommandChannelVolume, string(channel)+" "+formatTelnetVolume(level+CHANNEL_VOLUME_OFFSET))
+	return err
+}
+
+func (d *DenonAVR) ChannelVolumeUp(channel DenonChannel) error {
+
+	_, err := d.sendCommandToDevice(DenonCommandChannelVolume, string(channel)+" UP")
+	return err
+}
+
+func (d *DenonAVR) ChannelVolumeDown(channel DenonChannel) error {
+
+	_, err := d.sendCommandToDevice(DenonCommandChannelVolume, string(channel)+" DOWN")
+	return err
+}
+
+// Reset the volume of all channels to 0dB
+func (d *DenonAVR) ResetChannelVolumes() error {
+
+	_, err := d.sendCommandToDevice(DenonCommandChannelVolume, "ZRL")
+	return err
+}
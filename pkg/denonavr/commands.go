@@ -1,6 +1,7 @@
 package denonavr
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -8,39 +9,113 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
-func (d *DenonAVR) sendCommandToDevice(cmd DenonCommand, payload string) (int, error) {
+// Result of a command sent to the device
+type DenonCommandResult string
+
+const (
+	DenonCommandResultAccepted     DenonCommandResult = "ACCEPTED"
+	DenonCommandResultTimedOut     DenonCommandResult = "TIMED_OUT"
+	DenonCommandResultRejected     DenonCommandResult = "REJECTED"
+	DenonCommandResultDisconnected DenonCommandResult = "DISCONNECTED"
+)
+
+// Return the status code of the result as reported to the Remote Two
+func (r DenonCommandResult) StatusCode() int {
+
+	switch r {
+	case DenonCommandResultAccepted:
+		return 200
+	case DenonCommandResultRejected:
+		return 400
+	case DenonCommandResultTimedOut:
+		return 408
+	}
+
+	return 503
+}
+
+// Error returned when a command was not accepted by the device
+type DenonCommandError struct {
+	Result DenonCommandResult
+	Err    error
+}
+
+func (e *DenonCommandError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Result, e.Err)
+}
+
+func (e *DenonCommandError) Unwrap() error {
+	return e.Err
+}
+
+// Return the result of a command from the error returned when sending it
+func GetCommandResult(err error) DenonCommandResult {
+
+	if err == nil {
+		return DenonCommandResultAccepted
+	}
+
+	var commandError *DenonCommandError
+	if errors.As(err, &commandError) {
+		return commandError.Result
+	}
+
+	return DenonCommandResultRejected
+}
+
+// Send a command to the device through the command queue
+func (d *DenonAVR) sendCommandToDevice(cmd DenonCommand, payload string) (DenonCommandResult, error) {
+
+	result, err := d.commandQueue.sendCommand(cmd, payload, d.writeCommandToDevice)
+	if err != nil {
+		return result, &DenonCommandError{Result: result, Err: err}
+	}
+
+	return result, nil
+}
+
+func (d *DenonAVR) writeCommandToDevice(cmd DenonCommand, payload string) (DenonCommandResult, error) {
 
 	if d.telnetEnabled {
 
-		err := d.sendTelnetCommand(cmd, payload)
-		if err != nil {
+		result, err := d.sendTelnetCommandAndWait(cmd, payload)
+		if result == DenonCommandResultDisconnected {
 			// Fallback to HTTP
 			return d.sendHTTPCommand(cmd, payload)
 		}
 
-		return 200, nil
+		return result, err
 	}
 
 	return d.sendHTTPCommand(cmd, payload)
 }
 
-func (d *DenonAVR) sendHTTPCommand(denonCommandType DenonCommand, command string) (int, error) {
+func (d *DenonAVR) sendHTTPCommand(denonCommandType DenonCommand, command string) (DenonCommandResult, error) {
 
-	url := "http://" + d.Host + COMMAND_URL + "?" + url.QueryEscape(string(denonCommandType)+command)
+	url := d.getURL(COMMAND_URL) + "?" + url.QueryEscape(string(denonCommandType)+command)
 	log.WithFields(log.Fields{
 		"type":    string(denonCommandType),
 		"command": command,
 		"url":     url}).Info("Send Command to Denon Device")
 
-	req, err := http.Get(url)
+	resp, err := d.httpClient.Get(url)
 	if err != nil {
-		return req.StatusCode, fmt.Errorf("error sending command: %w", err)
+		return DenonCommandResultDisconnected, fmt.Errorf("error sending command: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return DenonCommandResultRejected, fmt.Errorf("command rejected with status code %d", resp.StatusCode)
 	}
 
 	// Trigger a update to get updated data handled in the Listen Loop
-	d.updateTrigger <- "update"
+	// Don't block the command queue when the listen loop is not running
+	select {
+	case d.updateTrigger <- "update":
+	default:
+	}
 
-	return req.StatusCode, nil
+	return DenonCommandResultAccepted, nil
 }
 
 func (d *DenonAVR) SetMoni1Out() error {
@@ -13,8 +13,8 @@ const (
 	DenonCursorControlMenuInfo DenonCursorControl = "INF"
 )
 
-func (d *DenonAVR) CursorControl(cursorControl DenonCursorControl) int {
-	status, _ := d.sendCommandToDevice(DenonCommandCursorControl, string(cursorControl))
+func (d *DenonAVR) CursorControl(cursorControl DenonCursorControl) DenonCommandResult {
+	result, _ := d.sendCommandToDevice(DenonCommandCursorControl, string(cursorControl))
 
-	return status
+	return result
 }
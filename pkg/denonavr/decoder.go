@@ -0,0 +1,202 @@
+package denonavr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type DenonDynamicRangeCompression string
+
+const (
+	DenonDynamicRangeCompressionAuto DenonDynamicRangeCompression = "AUTO"
+	DenonDynamicRangeCompressionLow  DenonDynamicRangeCompression = "LOW"
+	DenonDynamicRangeCompressionMid  DenonDynamicRangeCompression = "MID"
+	DenonDynamicRangeCompressionHigh DenonDynamicRangeCompression = "HI"
+	DenonDynamicRangeCompressionOff  DenonDynamicRangeCompression = "OFF"
+)
+
+var DYNAMIC_RANGE_COMPRESSION_MAPPING = map[string]DenonDynamicRangeCompression{
+	"AUTO": DenonDynamicRangeCompressionAuto,
+	"LOW":  DenonDynamicRangeCompressionLow,
+	"MID":  DenonDynamicRangeCompressionMid,
+	"HIGH": DenonDynamicRangeCompressionHigh,
+	"OFF":  DenonDynamicRangeCompressionOff,
+}
+
+// The LFE level is sent as attenuation, PSLFE 00 is 0dB, PSLFE 10 is -10dB
+const (
+	LFE_LEVEL_MIN float64 = -10
+	LFE_LEVEL_MAX float64 = 0
+)
+
+// The decoder post processing settings and upmixer options depend on the input signal and the surround mode
+// They are queried again after each surround mode change
+var DECODER_SETTING_QUERIES = []string{
+	"DRC ?",
+	"LFE ?",
+	"CINEMA EQ. ?",
+	"LOM ?",
+	"SPV ?",
+	"NEURAL ?",
+	"CES ?",
+	"HEG ?",
+}
+
+// Query the decoder post processing settings
+// The replies are handled as telnet events, without telnet the settings are not available
+func (d *DenonAVR) queryDecoderSettings() {
+
+	if !d.telnetEnabled {
+		return
+	}
+
+	log.Debug("Query decoder settings via telnet")
+
+	for _, query := range DECODER_SETTING_QUERIES {
+		if _, err := d.commandQueue.sendCommand(DenonCommandPS, query, d.sendTelnetQuery); err != nil {
+			return
+		}
+	}
+}
+
+// PSDRC AUTO, PSLFE 05, PSCINEMA EQ.ON, PSLOM ON
+func (d *DenonAVR) handleDecoderSettingTelnetEvent(attribute string, param string) {
+
+	if attribute == "LFELevel" {
+		attenuation, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			log.WithError(err).Error("failed to parse LFE level")
+			return
+		}
+		param = fmt.Sprintf("%0.1f", -attenuation)
+	}
+
+	d.SetAttribute(attribute, param)
+	d.SetAttribute("DecoderSettings", d.getDecoderSettingsText())
+}
+
+// Summary of the known decoder settings, e.g. DRC AUTO · LFE -5.0dB · Cinema EQ OFF · Loudness ON
+func (d *DenonAVR) getDecoderSettingsText() string {
+
+	var settings []string
+	for _, setting := range []struct {
+		attribute string
+		name      string
+		unit      string
+	}{
+		{"DynamicRangeCompression", "DRC", ""},
+		{"LFELevel", "LFE", "dB"},
+		{"CinemaEQ", "Cinema EQ", ""},
+		{"LoudnessManagement", "Loudness", ""},
+	} {
+		if value, err := d.GetAttribute(setting.attribute); err == nil {
+			settings = append(settings, fmt.Sprintf("%s %s%s", setting.name, value.(string), setting.unit))
+		}
+	}
+
+	return strings.Join(settings, " · ")
+}
+
+func (d *DenonAVR) GetDynamicRangeCompression() (DenonDynamicRangeCompression, error) {
+
+	drc, err := d.GetAttribute("DynamicRangeCompression")
+	if err != nil {
+		return "", err
+	}
+
+	return DenonDynamicRangeCompression(drc.(string)), nil
+}
+
+// PSDRC AUTO
+func (d *DenonAVR) SetDynamicRangeCompression(drc DenonDynamicRangeCompression) error {
+
+	for _, c := range DYNAMIC_RANGE_COMPRESSION_MAPPING {
+		if c == drc {
+			_, err := d.sendCommandToDevice(DenonCommandPS, "DRC "+string(drc))
+			return err
+		}
+	}
+
+	return fmt.Errorf("invalid dynamic range compression setting %s", drc)
+}
+
+// Return the LFE level in dB
+func (d *DenonAVR) GetLFELevel() (float64, error) {
+
+	level, err := d.GetAttribute("LFELevel")
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseFloat(level.(string), 64)
+}
+
+// Set the LFE level from -10dB to 0dB, e.g. PSLFE 05 for -5dB
+func (d *DenonAVR) SetLFELevel(level float64) error {
+
+	if level < LFE_LEVEL_MIN || level > LFE_LEVEL_MAX || level != float64(int(level)) {
+		return fmt.Errorf("LFE level %0.1f out of range", level)
+	}
+
+	_, err := d.sendCommandToDevice(DenonCommandPS, fmt.Sprintf("LFE %02d", int(-level)))
+	return err
+}
+
+func (d *DenonAVR) LFELevelUp() error {
+	_, err := d.sendCommandToDevice(DenonCommandPS, "LFE UP")
+	return err
+}
+
+func (d *DenonAVR) LFELevelDown() error {
+	_, err := d.sendCommandToDevice(DenonCommandPS, "LFE DOWN")
+	return err
+}
+
+func (d *DenonAVR) CinemaEQEnabled() bool {
+
+	cinemaEQ, err := d.GetAttribute("CinemaEQ")
+	if err != nil {
+		log.WithError(err).Debug("CinemaEQ attribute not found")
+		return false
+	}
+
+	return cinemaEQ.(string) == "ON"
+}
+
+// PSCINEMA EQ.ON
+func (d *DenonAVR) SetCinemaEQ(enabled bool) error {
+
+	state := "OFF"
+	if enabled {
+		state = "ON"
+	}
+
+	_, err := d.sendCommandToDevice(DenonCommandPS, "CINEMA EQ."+state)
+	return err
+}
+
+func (d *DenonAVR) LoudnessManagementEnabled() bool {
+
+	lom, err := d.GetAttribute("LoudnessManagement")
+	if err != nil {
+		log.WithError(err).Debug("LoudnessManagement attribute not found")
+		return false
+	}
+
+	return lom.(string) == "ON"
+}
+
+// PSLOM ON
+func (d *DenonAVR) SetLoudnessManagement(enabled bool) error {
+
+	state := "OFF"
+	if enabled {
+		state = "ON"
+	}
+
+	_, err := d.sendCommandToDevice(DenonCommandPS, "LOM "+state)
+	return err
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -13,6 +14,7 @@ import (
 	"net/http"
 
 	"github.com/ziutek/telnet"
+	"k8s.io/utils/strings/slices"
 )
 
 type DenonCommand string
@@ -30,6 +32,16 @@ const (
 	DenonCommandVS             DenonCommand = "VS"
 	DenonCommandZone2          DenonCommand = "Z2"
 	DenonCommandZone3          DenonCommand = "Z3"
+	DenonCommandChannelVolume  DenonCommand = "CV"
+	DenonCommandPS             DenonCommand = "PS"
+	DenonCommandTunerFrequency DenonCommand = "TF"
+	DenonCommandTunerPreset    DenonCommand = "TP"
+	DenonCommandTunerMode      DenonCommand = "TM"
+	// Tuner preset names, part of the OP (option) commands
+	DenonCommandTunerPresetName DenonCommand = "OPTPN"
+	DenonCommandSleep           DenonCommand = "SLP"
+	DenonCommandECO             DenonCommand = "ECO"
+	DenonCommandDimmer          DenonCommand = "DIM"
 )
 
 const (
@@ -45,8 +57,14 @@ const (
 	MAINZONE_URL         string = "/goform/formMainZone_MainZoneXml.xml"
 	COMMAND_URL          string = "/goform/formiPhoneAppDirect.xml"
 	NET_AUDIO_STATUR_URL string = "/goform/formNetAudio_StatusXml.xml"
+	APPCOMMAND_URL       string = "/goform/AppCommand.xml"
+	APPCOMMAND0300_URL   string = "/goform/AppCommand0300.xml"
+	DEVICEINFO_URL       string = "/goform/Deviceinfo.xml"
 )
 
+// The AppCommand API has no ECO mode and brand, the main zone data is polled less often for them
+const MAIN_ZONE_DATA_UPDATE_INTERVAL = 60 * time.Second
+
 type DenonXML struct {
 	XMLName          xml.Name     `xml:"item"`
 	FriendlyName     string       `xml:"FriendlyName>value"`
@@ -80,14 +98,25 @@ type ValueLists struct {
 
 type DenonAVR struct {
 	Host string
+	// Scheme, host and port of the control API, e.g. http://192.168.1.10:8080
+	BaseURL string
+
+	httpClient *http.Client
 
 	telnet *telnet.Conn
 
-	mainZoneData DenonXML
+	mainZoneData      DenonXML
+	mainZoneDataMutex sync.Mutex
+
+	// Capabilities of the receiver, nil until UpdateDeviceInfo is called
+	deviceInfo      *DeviceInfo
+	deviceInfoMutex sync.Mutex
 
 	// Zone Status
 	zoneStatus     map[DenonZone]DenonZoneStatus
 	netAudioStatus DenonNetAudioStatus
+	// Guards the zone status and the net audio status, they are updated from the poll loop and the input list update
+	statusMutex sync.Mutex
 
 	// Attributes
 	attributes     map[string]interface{}
@@ -96,19 +125,42 @@ type DenonAVR struct {
 	updateTrigger  chan string
 	controlChannel chan string
 
+	// Poll the state of all zones with one AppCommand request
+	// Disabled when the receiver does not support the AppCommand API or returns invalid responses repeatedly
+	appCommandSupported atomic.Bool
+	appCommandFailures  atomic.Int32
+	// Get and set the advanced audio settings with the AppCommand0300 API
+	// Disabled when the receiver does not support the AppCommand0300 API or returns invalid responses repeatedly
+	appCommand0300Supported atomic.Bool
+	appCommand0300Failures  atomic.Int32
+	// Unix time in nanoseconds until the AppCommand0300 API is not used after a failed request
+	appCommand0300RetryAt atomic.Int64
+
+	// All commands are sent through this queue
+	commandQueue *commandQueue
+
 	// Telnet
 	telnetEnabled bool
-	telnetEvents  chan *TelnetEvent
-	telnetMutex   sync.Mutex
+	// Only use telnet to get the state of the device, no HTTP polling
+	telnetOnly   bool
+	telnetEvents chan *TelnetEvent
+	telnetMutex  sync.Mutex
+
+	// Telnet commands waiting for their echo
+	telnetAcknowledgements []*telnetAcknowledgement
+	acknowledgementMutex   sync.Mutex
 
 	entityChangedFunction map[string][]func(interface{})
 }
 
-func NewDenonAVR(host string, telnetEnabled bool) *DenonAVR {
+func NewDenonAVR(host string, telnetEnabled bool, telnetOnly bool) *DenonAVR {
 
 	denonavr := DenonAVR{}
 
 	denonavr.Host = host
+	// Receivers before 2016 serve the control API on port 80, use DetectBaseURL for newer receivers
+	denonavr.BaseURL = "http://" + host
+	denonavr.httpClient = newHTTPClient(HTTP_TIMEOUT)
 
 	denonavr.mainZoneData = DenonXML{}
 	denonavr.zoneStatus = make(map[DenonZone]DenonZoneStatus)
@@ -122,7 +174,19 @@ func NewDenonAVR(host string, telnetEnabled bool) *DenonAVR {
 	denonavr.controlChannel = make(chan string)
 	denonavr.telnetEvents = make(chan *TelnetEvent)
 
+	denonavr.commandQueue = newCommandQueue(COMMAND_INTERVAL)
+
+	denonavr.appCommandSupported.Store(true)
+	denonavr.appCommand0300Supported.Store(true)
+
 	denonavr.telnetEnabled = telnetEnabled
+	// telnet only mode requires telnet
+	denonavr.telnetOnly = telnetEnabled && telnetOnly
+
+	// Decoder settings depend on the surround mode, refresh them after each change
+	denonavr.AddHandleEntityChangeFunc("MainZoneSurroundMode", func(value interface{}) {
+		denonavr.queryDecoderSettings()
+	})
 
 	return &denonavr
 }
@@ -143,8 +207,8 @@ func (d *DenonAVR) callEntityChangeFunction(attribute string, newValue interface
 
 func (d *DenonAVR) getMainZoneDataFromDevice() error {
 
-	d.mainZoneData = DenonXML{} // Somehow the values in the array are added instead of replaced. Not sure if this is the solution, but it works...
-	resp, err := http.Get("http://" + d.Host + MAINZONE_URL)
+	mainZoneData := DenonXML{} // Somehow the values in the array are added instead of replaced. Not sure if this is the solution, but it works...
+	resp, err := d.httpClient.Get(d.getURL(MAINZONE_URL))
 	if err != nil {
 		log.WithError(err).Error("Failed to get data from Denon AVR")
 		return err
@@ -156,14 +220,37 @@ func (d *DenonAVR) getMainZoneDataFromDevice() error {
 		return err
 	}
 
-	if err := xml.Unmarshal(body, &d.mainZoneData); err != nil {
+	if err := xml.Unmarshal(body, &mainZoneData); err != nil {
 		log.WithError(err).Info("Could not unmarshall")
 		return err
 	}
 
+	d.mainZoneDataMutex.Lock()
+	d.mainZoneData = mainZoneData
+	d.mainZoneDataMutex.Unlock()
+
 	return nil
 }
 
+// Return the main zone data of the last HTTP update
+func (d *DenonAVR) getMainZoneData() DenonXML {
+
+	d.mainZoneDataMutex.Lock()
+	defer d.mainZoneDataMutex.Unlock()
+
+	return d.mainZoneData
+}
+
+// Set the input function of the main zone data when it's received via telnet or the AppCommand API
+// Needed for the media title and image
+func (d *DenonAVR) setMainZoneInputFuncSelect(inputFuncSelect string) {
+
+	d.mainZoneDataMutex.Lock()
+	defer d.mainZoneDataMutex.Unlock()
+
+	d.mainZoneData.InputFuncSelect = inputFuncSelect
+}
+
 func (d *DenonAVR) StopListenLoop() {
 	log.Info("Stop Denon Listen Loop")
 	d.controlChannel <- "disconnect"
@@ -175,52 +262,64 @@ func (d *DenonAVR) StartListenLoop() error {
 
 	updateInterval := 5 * time.Second
 	ticker := time.NewTicker(updateInterval)
+	mainZoneDataTicker := time.NewTicker(MAIN_ZONE_DATA_UPDATE_INTERVAL)
 
 	telnetControlChannel := make(chan string)
 
 	defer func() {
 		ticker.Stop()
+		mainZoneDataTicker.Stop()
+		// Make sure telnet also disconnects
+		if d.telnetEnabled {
+			close(telnetControlChannel)
+		}
 		log.Debug("Denon Listen Loop stopped")
 	}()
 
+	// The input function lists and renamed sources are not available via telnet and the AppCommand API
+	// Get them in the background, so a hung web server does not delay the telnet connection
+	go d.updateInputFuncListsAndNotify()
+
 	// Start listening to telnet
 	if d.telnetEnabled {
-		go func() {
-			// Try reconnecting to telnet if reconnect is true
-			for {
-				log.Debug("Starting Telnet Loop")
-				if err, reconnect := d.listenTelnet(telnetControlChannel); err != nil {
-					close(telnetControlChannel)
-					d.controlChannel <- "telnet_error"
-					if !reconnect {
-						break
-					}
-				}
-			}
-		}()
+		go d.superviseTelnet(telnetControlChannel)
 	}
 
 	// do an intial update to make sure we have up to date values
-	d.updateAndNotify()
+	// In telnet only mode, this is done with the status queries after the telnet connect
+	if !d.telnetOnly {
+		// With AppCommand support, the main zone data is polled less often, get it now
+		if d.appCommandSupported.Load() {
+			go d.updateMainZoneDataAndNotify()
+		}
+		d.updateAndNotify()
+	}
 
 	for {
 		select {
 		case <-d.updateTrigger:
 			// force manual update
-			d.updateAndNotify()
+			if !d.telnetOnly {
+				d.updateAndNotify()
+			}
 		case <-ticker.C:
 			// Update every 5 Seconds
-			d.updateAndNotify()
+			// In telnet only mode, only the media information not available via telnet
+			if d.telnetOnly {
+				d.updateMediaAndNotify()
+			} else {
+				d.updateAndNotify()
+			}
+		case <-mainZoneDataTicker.C:
+			// Without AppCommand support, the main zone data is polled with each update
+			if !d.telnetOnly && d.appCommandSupported.Load() {
+				go d.updateMainZoneDataAndNotify()
+			}
 		case msg := <-d.controlChannel:
 			switch msg {
 			case "disconnect":
 				log.Debug("return listen loop due to disconnect")
-				// Make sure telnet also disconnects
-				telnetControlChannel <- "disconnect"
 				return nil
-			case "telnet_error":
-				log.Debug("return listen loop with error")
-				return fmt.Errorf("telnet connection error")
 			case "http_error":
 				log.Debug("return listen loop with error")
 				return fmt.Errorf("http connection error")
@@ -232,11 +331,28 @@ func (d *DenonAVR) StartListenLoop() error {
 
 func (d *DenonAVR) updateAndNotify() {
 
+	// Get the state of all zones in one round trip if supported
+	if d.appCommandSupported.Load() {
+		go d.updateAppCommandStatusAndNotify()
+		return
+	}
+
+	d.updateStatusFilesAndNotify()
+}
+
+// Get the state from the goform status files with one request per file
+func (d *DenonAVR) updateStatusFilesAndNotify() {
+
 	// Don't wait on each Call, handle them individually
 	go d.updateMainZoneDataAndNotify()
-	go d.updateZoneStatusAndNotify(MainZone)
-	go d.updateZoneStatusAndNotify(Zone2)
-	go d.updateZoneStatusAndNotify(Zone3)
+	for _, zone := range d.GetZones() {
+		go d.updateZoneStatusAndNotify(zone)
+	}
+
+	// Dirac and the speaker preset are only available with the AppCommand0300 API
+	if d.isAppCommand0300Available() {
+		go d.updateAppCommand0300SettingsAndNotify()
+	}
 }
 
 func (d *DenonAVR) updateMainZoneDataAndNotify() {
@@ -246,7 +362,9 @@ func (d *DenonAVR) updateMainZoneDataAndNotify() {
 		return
 	}
 
-	d.SetAttribute("POWER", d.mainZoneData.Power)
+	d.SetAttribute("POWER", d.getMainZoneData().Power)
+
+	d.updateECOModeAndNotify()
 
 	d.getNetAudioStatus()
 
@@ -257,16 +375,67 @@ func (d *DenonAVR) updateMainZoneDataAndNotify() {
 	d.getMediaImageURL()
 }
 
+// Update media title and image in telnet only mode
+// The net audio status is only available via HTTP, so only get it for playing sources
+func (d *DenonAVR) updateMediaAndNotify() {
+
+	if d.IsOn(MainZone) && slices.Contains(PLAYING_SOURCES, d.getMainZoneData().InputFuncSelect) {
+		d.getNetAudioStatus()
+	}
+
+	// Media Title
+	d.getMediaTitle()
+
+	// Media Image URL
+	d.getMediaImageURL()
+}
+
+// Get the input function lists of all zones
+// Used in telnet only mode, as the renamed and deleted sources are only available via HTTP
+func (d *DenonAVR) updateInputFuncListsAndNotify() {
+
+	for _, zone := range d.GetZones() {
+		d.getZoneStatus(zone)
+		d.updateZoneInputFuncListAndNotify(zone)
+	}
+
+	// The sources received via telnet in the meantime are not renamed yet, query them again
+	if d.telnetEnabled {
+		for _, zone := range d.GetZones() {
+			cmd := d.getZoneCommand(zone)
+			if zone == MainZone {
+				cmd = DenonCommandSelectInput
+			}
+			if _, err := d.commandQueue.sendLowPriorityCommand(cmd, "?", d.sendTelnetQuery); err != nil {
+				return
+			}
+		}
+	}
+}
+
 func (d *DenonAVR) updateZoneStatusAndNotify(zone DenonZone) {
 
 	// Get Data from Denon AVR
 	zoneStatus := d.getZoneStatus(zone)
-	zoneName := d.getZoneName(zone)
+	zoneName := d.GetZoneName(zone)
 
 	d.SetAttribute(zoneName+"Power", zoneStatus.Power)
 	d.SetAttribute(zoneName+"Volume", zoneStatus.MasterVolume)
 	d.SetAttribute(zoneName+"Mute", zoneStatus.Mute)
-	d.SetAttribute(zoneName+"SurroundMode", zoneStatus.SurrMode)
+	// Zone2 and Zone3 have no surround mode, only a channel setting
+	if zone == MainZone {
+		d.SetAttribute(zoneName+"SurroundMode", d.getZoneSurroundMode(zoneStatus))
+	}
+
+	d.updateZoneInputFuncListAndNotify(zone)
+
+	d.SetAttribute(zoneName+"InputFuncSelect", d.getRenamedInputFuncSelect(zone, zoneStatus.InputFuncSelect))
+
+}
+
+func (d *DenonAVR) updateZoneInputFuncListAndNotify(zone DenonZone) {
+
+	zoneName := d.GetZoneName(zone)
 
 	// We use the renamed input sources
 	inputFuncSelectList := d.GetZoneInputFuncList(zone)
@@ -278,24 +447,25 @@ func (d *DenonAVR) updateZoneStatusAndNotify(zone DenonZone) {
 	// sort the slice by keys
 	sort.Strings(inputList)
 	d.SetAttribute(zoneName+"InputFuncList", inputList)
+}
 
-	inputFuncSelect := zoneStatus.InputFuncSelect
-	// Rename Source with the SOURCE_MAPPING if necessary
-	for source, origin := range SOURCE_MAPPING {
-		if origin == zoneStatus.InputFuncSelect {
-			inputFuncSelect = source
-			break
-		}
-	}
-	// And then custom renames
-	if inputFuncSelectList[inputFuncSelect] != "" {
-		inputFuncSelect = inputFuncSelectList[inputFuncSelect]
+// Return the command used to control a zone
+// ZM for the main zone, Z2 and Z3 for the other zones
+func (d *DenonAVR) getZoneCommand(zone DenonZone) DenonCommand {
+
+	switch zone {
+	case Zone2:
+		return DenonCommandZone2
+
+	case Zone3:
+		return DenonCommandZone3
 	}
-	d.SetAttribute(zoneName+"InputFuncSelect", inputFuncSelect)
 
+	return DennonCommandZoneMain
 }
 
-func (d *DenonAVR) getZoneName(zone DenonZone) string {
+// Return the name of a zone as used in the attributes
+func (d *DenonAVR) GetZoneName(zone DenonZone) string {
 
 	switch zone {
 	case MainZone:
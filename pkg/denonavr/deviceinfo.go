@@ -0,0 +1,266 @@
+package denonavr
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Ports and paths of the UPnP device description, in the order they are tried
+var UPNP_DESCRIPTION_URLS = []struct {
+	Port string
+	Path string
+}{
+	{"8080", "/description.xml"},
+	{"60006", "/upnp/desc/aios_device/aios_device.xml"},
+}
+
+type DenonDeviceInfoXML struct {
+	XMLName                xml.Name                         `xml:"Device_Info"`
+	CommApiVers            string                           `xml:"CommApiVers"`
+	BrandCode              string                           `xml:"BrandCode"`
+	ModelName              string                           `xml:"ModelName"`
+	MacAddress             string                           `xml:"MacAddress"`
+	UpgradeVersion         string                           `xml:"UpgradeVersion"`
+	DeviceZones            int                              `xml:"DeviceZones"`
+	DeviceZoneCapabilities []DenonDeviceZoneCapabilitiesXML `xml:"DeviceZoneCapabilities"`
+}
+
+// Capabilities of a zone, 0 is the main zone
+type DenonDeviceZoneCapabilitiesXML struct {
+	No             string   `xml:"Zone>No"`
+	InputFunctions []string `xml:"InputSource>List>Source>FuncName"`
+	SoundModes     []string `xml:"SoundMode>List>Genre>Name"`
+}
+
+type DenonUPnPDescription struct {
+	XMLName      xml.Name `xml:"root"`
+	FriendlyName string   `xml:"device>friendlyName"`
+	Manufacturer string   `xml:"device>manufacturer"`
+	ModelName    string   `xml:"device>modelName"`
+	SerialNumber string   `xml:"device>serialNumber"`
+}
+
+// Capabilities of the receiver read from the Deviceinfo.xml and the UPnP description
+// Empty lists mean the receiver did not report them, everything is assumed to be supported then
+type DeviceInfo struct {
+	ModelName       string
+	FriendlyName    string
+	Manufacturer    string
+	SerialNumber    string
+	FirmwareVersion string
+	CommApiVersion  string
+	// 0 for Denon, 1 for Marantz
+	BrandCode string
+	// Number of zones including the main zone
+	Zones           int
+	InputFunctions  map[DenonZone][]string
+	SoundModeGroups []string
+}
+
+func (i *DeviceInfo) IsMarantz() bool {
+	return i.BrandCode == "1" || strings.Contains(strings.ToUpper(i.Manufacturer), "MARANTZ")
+}
+
+// Return the zones of the receiver
+func (i *DeviceInfo) GetZones() []DenonZone {
+
+	zones := []DenonZone{MainZone, Zone2, Zone3}
+	if i.Zones > 0 && i.Zones < len(zones) {
+		return zones[:i.Zones]
+	}
+
+	return zones
+}
+
+func (i *DeviceInfo) HasZone(zone DenonZone) bool {
+
+	for _, z := range i.GetZones() {
+		if z == zone {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Return if the receiver reports an input function for a zone
+// The device info uses the names of the status files or of the telnet commands, e.g. CBL/SAT or SAT/CBL
+func (i *DeviceInfo) SupportsInputFunction(zone DenonZone, inputFunction string) bool {
+
+	if len(i.InputFunctions[zone]) == 0 {
+		return true
+	}
+
+	names := []string{inputFunction}
+	for source, origin := range SOURCE_MAPPING {
+		if strings.EqualFold(source, inputFunction) {
+			names = append(names, origin)
+		}
+		if strings.EqualFold(origin, inputFunction) {
+			names = append(names, source)
+		}
+	}
+
+	for _, f := range i.InputFunctions[zone] {
+		for _, name := range names {
+			if strings.EqualFold(f, name) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Return if a sound mode of the SOUND_MODE_MAPPING is supported
+// Either the mode itself or one of its surround modes must be reported by the receiver
+func (i *DeviceInfo) SupportsSoundMode(mode string) bool {
+
+	if len(i.SoundModeGroups) == 0 {
+		return true
+	}
+
+	for _, group := range i.SoundModeGroups {
+		if strings.EqualFold(group, mode) {
+			return true
+		}
+		for _, surroundMode := range SOUND_MODE_MAPPING[mode] {
+			if strings.EqualFold(group, surroundMode) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Read the capabilities of the receiver
+// The UPnP description is optional, it only adds the friendly name, manufacturer and serial number
+func (d *DenonAVR) UpdateDeviceInfo() (*DeviceInfo, error) {
+
+	deviceInfoXML := DenonDeviceInfoXML{}
+	if err := d.getXMLFromDevice(d.getURL(DEVICEINFO_URL), &deviceInfoXML); err != nil {
+		return nil, fmt.Errorf("cannot get device info: %w", err)
+	}
+
+	deviceInfo := DeviceInfo{
+		ModelName:       strings.TrimPrefix(strings.TrimSpace(deviceInfoXML.ModelName), "*"),
+		FirmwareVersion: strings.TrimSpace(deviceInfoXML.UpgradeVersion),
+		CommApiVersion:  strings.TrimSpace(deviceInfoXML.CommApiVers),
+		BrandCode:       strings.TrimSpace(deviceInfoXML.BrandCode),
+		Zones:           deviceInfoXML.DeviceZones,
+		InputFunctions:  make(map[DenonZone][]string),
+	}
+
+	zones := []DenonZone{MainZone, Zone2, Zone3}
+	for i, capabilities := range deviceInfoXML.DeviceZoneCapabilities {
+		zoneIndex, err := strconv.Atoi(strings.TrimSpace(capabilities.No))
+		if err != nil {
+			zoneIndex = i
+		}
+		if zoneIndex < 0 || zoneIndex >= len(zones) {
+			continue
+		}
+		for _, inputFunction := range capabilities.InputFunctions {
+			deviceInfo.InputFunctions[zones[zoneIndex]] = append(deviceInfo.InputFunctions[zones[zoneIndex]], strings.TrimSpace(inputFunction))
+		}
+		if zones[zoneIndex] == MainZone {
+			for _, soundMode := range capabilities.SoundModes {
+				deviceInfo.SoundModeGroups = append(deviceInfo.SoundModeGroups, strings.TrimSpace(soundMode))
+			}
+		}
+	}
+
+	if description, err := d.getUPnPDescription(); err == nil {
+		deviceInfo.FriendlyName = description.FriendlyName
+		deviceInfo.Manufacturer = description.Manufacturer
+		deviceInfo.SerialNumber = description.SerialNumber
+		if deviceInfo.ModelName == "" {
+			deviceInfo.ModelName = description.ModelName
+		}
+	} else {
+		log.WithError(err).Debug("UPnP description not available")
+	}
+
+	log.WithFields(log.Fields{
+		"model":    deviceInfo.ModelName,
+		"firmware": deviceInfo.FirmwareVersion,
+		"zones":    deviceInfo.Zones,
+	}).Info("Got device info of Denon AVR")
+
+	d.deviceInfoMutex.Lock()
+	d.deviceInfo = &deviceInfo
+	d.deviceInfoMutex.Unlock()
+
+	return &deviceInfo, nil
+}
+
+// Return the capabilities of the receiver, nil if they are not known
+func (d *DenonAVR) GetDeviceInfo() *DeviceInfo {
+
+	d.deviceInfoMutex.Lock()
+	defer d.deviceInfoMutex.Unlock()
+
+	return d.deviceInfo
+}
+
+// Return the zones of the receiver, all zones if the device info is not known
+func (d *DenonAVR) GetZones() []DenonZone {
+
+	deviceInfo := d.GetDeviceInfo()
+	if deviceInfo == nil {
+		return []DenonZone{MainZone, Zone2, Zone3}
+	}
+
+	return deviceInfo.GetZones()
+}
+
+func (d *DenonAVR) getUPnPDescription() (*DenonUPnPDescription, error) {
+
+	host := d.Host
+	if u, err := url.Parse(d.BaseURL); err == nil && u.Hostname() != "" {
+		host = u.Hostname()
+	}
+
+	var err error
+	for _, descriptionURL := range UPNP_DESCRIPTION_URLS {
+		description := DenonUPnPDescription{}
+		if err = d.getXMLFromDevice("http://"+net.JoinHostPort(host, descriptionURL.Port)+descriptionURL.Path, &description); err == nil {
+			return &description, nil
+		}
+	}
+
+	return nil, err
+}
+
+func (d *DenonAVR) getXMLFromDevice(url string, v interface{}) error {
+	return getXML(d.httpClient, url, v)
+}
+
+func getXML(client *http.Client, url string, v interface{}) error {
+
+	resp, err := client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("got status code %d from %s", resp.StatusCode, url)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	return xml.Unmarshal(body, v)
+}
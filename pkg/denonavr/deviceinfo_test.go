@@ -0,0 +1,43 @@
+package denonavr
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestGetSoundModeList(t *testing.T) {
+
+	tests := []struct {
+		name            string
+		soundModeGroups []string
+		want            []string
+		wantMissing     []string
+	}{
+		{"unknown device info", nil, []string{"MOVIE", "AURO3D", "PURE DIRECT"}, nil},
+		{"no sound modes reported", []string{}, []string{"MOVIE", "AURO3D", "PURE DIRECT"}, nil},
+		{"mode reported", []string{"Movie", "Music", "Game", "Pure"}, []string{"MOVIE", "MUSIC", "GAME"}, []string{"AURO3D", "DOLBY DIGITAL"}},
+		{"surround mode reported", []string{"Direct", "Pure Direct", "Auro-3D"}, []string{"DIRECT", "PURE DIRECT", "AURO3D"}, []string{"MOVIE", "AURO2DSURR"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			d := NewDenonAVR("192.168.1.10", false, false)
+			if tt.soundModeGroups != nil {
+				d.deviceInfo = &DeviceInfo{Zones: 1, SoundModeGroups: tt.soundModeGroups}
+			}
+
+			soundModes := d.GetSoundModeList(MainZone)
+			for _, mode := range tt.want {
+				if !slices.Contains(soundModes, mode) {
+					t.Errorf("GetSoundModeList() = %v, want %s", soundModes, mode)
+				}
+			}
+			for _, mode := range tt.wantMissing {
+				if slices.Contains(soundModes, mode) {
+					t.Errorf("GetSoundModeList() = %v, want without %s", soundModes, mode)
+				}
+			}
+		})
+	}
+}
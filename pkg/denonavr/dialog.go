@@ -0,0 +1,217 @@
+package denonavr
+
+import (
+	"fmt"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type DenonDialogEnhancer string
+
+const (
+	DenonDialogEnhancerOff    DenonDialogEnhancer = "OFF"
+	DenonDialogEnhancerLow    DenonDialogEnhancer = "LOW"
+	DenonDialogEnhancerMedium DenonDialogEnhancer = "MED"
+	DenonDialogEnhancerHigh   DenonDialogEnhancer = "HIGH"
+)
+
+var DIALOG_ENHANCER_MAPPING = map[string]DenonDialogEnhancer{
+	"OFF":    DenonDialogEnhancerOff,
+	"LOW":    DenonDialogEnhancerLow,
+	"MEDIUM": DenonDialogEnhancerMedium,
+	"HIGH":   DenonDialogEnhancerHigh,
+}
+
+// Dialog level and subwoofer level are sent relative to 50 (0dB) and can be set from -12dB to +12dB in 0.5dB steps
+const (
+	DIALOG_LEVEL_OFFSET float64 = 50
+	DIALOG_LEVEL_MIN    float64 = -12
+	DIALOG_LEVEL_MAX    float64 = 12
+	DIALOG_LEVEL_STEP   float64 = 0.5
+
+	SUBWOOFER_LEVEL_OFFSET float64 = 50
+	SUBWOOFER_LEVEL_MIN    float64 = -12
+	SUBWOOFER_LEVEL_MAX    float64 = 12
+	SUBWOOFER_LEVEL_STEP   float64 = 0.5
+)
+
+// Handle a level event which is either the state of the adjustment or the level itself
+// PSDIL ON, PSDIL 52, PSSWL OFF, PSSWL 495
+func (d *DenonAVR) handleAdjustableLevelTelnetEvent(attribute string, param string, offset float64) {
+
+	if param == "ON" || param == "OFF" {
+		d.SetAttribute(attribute+"State", param)
+		return
+	}
+
+	level, err := parseTelnetLevel(param, offset)
+	if err != nil {
+		log.WithError(err).WithField("attribute", attribute).Error("failed to parse level")
+		return
+	}
+
+	d.SetAttribute(attribute, fmt.Sprintf("%0.1f", level))
+}
+
+func (d *DenonAVR) getAdjustableLevel(attribute string) (float64, error) {
+
+	level, err := d.GetAttribute(attribute)
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseFloat(level.(string), 64)
+}
+
+func (d *DenonAVR) adjustableLevelEnabled(attribute string) bool {
+
+	state, err := d.GetAttribute(attribute + "State")
+	if err != nil {
+		log.WithError(err).WithField("attribute", attribute).Debug("State attribute not found")
+		return false
+	}
+
+	return state.(string) == "ON"
+}
+
+// The AppCommand0300 command and parameter of each adjustable level
+var APPCOMMAND0300_ADJUSTABLE_LEVEL_STATES = map[string]struct {
+	Attribute string
+	Command   DenonAppCommand0300
+	Param     string
+}{
+	"DIL": {"DialogLevel", DenonAppCommand0300SetSoundDetail, APPCOMMAND0300_PARAM_DIALOG_LEVEL_ADJUST},
+	"SWL": {"SubwooferLevel", DenonAppCommand0300SetSubwoofer, APPCOMMAND0300_PARAM_SUBWOOFER_LEVEL_ADJUST},
+}
+
+func (d *DenonAVR) setAdjustableLevelState(parameter string, enabled bool) error {
+
+	state := "OFF"
+	if enabled {
+		state = "ON"
+	}
+
+	if appCommand, ok := APPCOMMAND0300_ADJUSTABLE_LEVEL_STATES[parameter]; ok && d.setAppCommand0300Parameter(appCommand.Command, appCommand.Param, formatAppCommand0300Bool(enabled)) {
+		d.SetAttribute(appCommand.Attribute+"State", state)
+		return nil
+	}
+
+	_, err := d.sendCommandToDevice(DenonCommandPS, parameter+" "+state)
+	return err
+}
+
+// Return the dialog level adjustment in dB
+func (d *DenonAVR) GetDialogLevel() (float64, error) {
+	return d.getAdjustableLevel("DialogLevel")
+}
+
+// Set the dialog level adjustment in dB, e.g. PSDIL 53 for +3dB
+func (d *DenonAVR) SetDialogLevel(level float64) error {
+
+	if level < DIALOG_LEVEL_MIN || level > DIALOG_LEVEL_MAX {
+		return fmt.Errorf("dialog level %0.1f out of range", level)
+	}
+
+	if !isLevelStep(level, DIALOG_LEVEL_STEP) {
+		return fmt.Errorf("dialog level %0.1f is not a multiple of %0.1fdB", level, DIALOG_LEVEL_STEP)
+	}
+
+	if d.setAppCommand0300Parameter(DenonAppCommand0300SetSoundDetail, APPCOMMAND0300_PARAM_DIALOG_LEVEL, formatAppCommand0300Level(level)) {
+		d.SetAttribute("DialogLevel", fmt.Sprintf("%0.1f", level))
+		return nil
+	}
+
+	_, err := d.sendCommandToDevice(DenonCommandPS, "DIL "+formatTelnetVolume(level+DIALOG_LEVEL_OFFSET))
+	return err
+}
+
+func (d *DenonAVR) DialogLevelUp() error {
+	_, err := d.sendCommandToDevice(DenonCommandPS, "DIL UP")
+	return err
+}
+
+func (d *DenonAVR) DialogLevelDown() error {
+	_, err := d.sendCommandToDevice(DenonCommandPS, "DIL DOWN")
+	return err
+}
+
+// The dialog level is only applied when the dialog level adjustment is enabled
+func (d *DenonAVR) DialogLevelEnabled() bool {
+	return d.adjustableLevelEnabled("DialogLevel")
+}
+
+func (d *DenonAVR) SetDialogLevelEnabled(enabled bool) error {
+	return d.setAdjustableLevelState("DIL", enabled)
+}
+
+func (d *DenonAVR) GetDialogEnhancer() (DenonDialogEnhancer, error) {
+
+	dialogEnhancer, err := d.GetAttribute("DialogEnhancer")
+	if err != nil {
+		return "", err
+	}
+
+	return DenonDialogEnhancer(dialogEnhancer.(string)), nil
+}
+
+// PSDIC MED
+func (d *DenonAVR) SetDialogEnhancer(dialogEnhancer DenonDialogEnhancer) error {
+
+	for _, e := range DIALOG_ENHANCER_MAPPING {
+		if e == dialogEnhancer {
+			if index, ok := getAppCommand0300Index(APPCOMMAND0300_DIALOG_ENHANCER_MAPPING, dialogEnhancer); ok && d.setAppCommand0300Parameter(DenonAppCommand0300SetSoundDetail, APPCOMMAND0300_PARAM_DIALOG_ENHANCER, index) {
+				d.SetAttribute("DialogEnhancer", string(dialogEnhancer))
+				return nil
+			}
+			_, err := d.sendCommandToDevice(DenonCommandPS, "DIC "+string(dialogEnhancer))
+			return err
+		}
+	}
+
+	return fmt.Errorf("invalid dialog enhancer setting %s", dialogEnhancer)
+}
+
+// Return the subwoofer level adjustment in dB
+func (d *DenonAVR) GetSubwooferLevel() (float64, error) {
+	return d.getAdjustableLevel("SubwooferLevel")
+}
+
+// Set the subwoofer level adjustment in dB, e.g. PSSWL 48 for -2dB
+func (d *DenonAVR) SetSubwooferLevel(level float64) error {
+
+	if level < SUBWOOFER_LEVEL_MIN || level > SUBWOOFER_LEVEL_MAX {
+		return fmt.Errorf("subwoofer level %0.1f out of range", level)
+	}
+
+	if !isLevelStep(level, SUBWOOFER_LEVEL_STEP) {
+		return fmt.Errorf("subwoofer level %0.1f is not a multiple of %0.1fdB", level, SUBWOOFER_LEVEL_STEP)
+	}
+
+	if d.setAppCommand0300Parameter(DenonAppCommand0300SetSubwoofer, APPCOMMAND0300_PARAM_SUBWOOFER_LEVEL, formatAppCommand0300Level(level)) {
+		d.SetAttribute("SubwooferLevel", fmt.Sprintf("%0.1f", level))
+		return nil
+	}
+
+	_, err := d.sendCommandToDevice(DenonCommandPS, "SWL "+formatTelnetVolume(level+SUBWOOFER_LEVEL_OFFSET))
+	return err
+}
+
+func (d *DenonAVR) SubwooferLevelUp() error {
+	_, err := d.sendCommandToDevice(DenonCommandPS, "SWL UP")
+	return err
+}
+
+func (d *DenonAVR) SubwooferLevelDown() error {
+	_, err := d.sendCommandToDevice(DenonCommandPS, "SWL DOWN")
+	return err
+}
+
+// The subwoofer level is only applied when the subwoofer level adjustment is enabled
+func (d *DenonAVR) SubwooferLevelEnabled() bool {
+	return d.adjustableLevelEnabled("SubwooferLevel")
+}
+
+func (d *DenonAVR) SetSubwooferLevelEnabled(enabled bool) error {
+	return d.setAdjustableLevelState("SWL", enabled)
+}
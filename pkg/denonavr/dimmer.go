@@ -0,0 +1,63 @@
+package denonavr
+
+import (
+	"fmt"
+	"strings"
+)
+
+type DenonDimmer string
+
+const (
+	DenonDimmerBright DenonDimmer = "BRI"
+	DenonDimmerDim    DenonDimmer = "DIM"
+	DenonDimmerDark   DenonDimmer = "DAR"
+	DenonDimmerOff    DenonDimmer = "OFF"
+)
+
+var DIMMER_MAPPING = map[string]DenonDimmer{
+	"BRIGHT": DenonDimmerBright,
+	"DIM":    DenonDimmerDim,
+	"DARK":   DenonDimmerDark,
+	"OFF":    DenonDimmerOff,
+}
+
+// DIM BRI, DIM DAR, ...
+func (d *DenonAVR) handleDimmerTelnetEvent(param string) {
+	d.SetAttribute("Dimmer", strings.TrimSpace(param))
+}
+
+// Return the current brightness of the front panel display
+func (d *DenonAVR) GetDimmer() (DenonDimmer, error) {
+
+	dimmer, err := d.GetAttribute("Dimmer")
+	if err != nil {
+		return "", err
+	}
+
+	return DenonDimmer(dimmer.(string)), nil
+}
+
+// DIM BRI
+func (d *DenonAVR) SetDimmer(dimmer DenonDimmer) error {
+
+	for _, m := range DIMMER_MAPPING {
+		if m == dimmer {
+			_, err := d.sendCommandToDevice(DenonCommandDimmer, " "+string(dimmer))
+			return err
+		}
+	}
+
+	return fmt.Errorf("invalid dimmer setting %s", dimmer)
+}
+
+// Switch to the next brightness of the front panel display, the receiver handles the order
+// DIM SEL
+func (d *DenonAVR) CycleDimmer() error {
+	_, err := d.sendCommandToDevice(DenonCommandDimmer, " SEL")
+	return err
+}
+
+// Check if a telnet event is a dimmer event
+func isTelnetDimmerEvent(data string) bool {
+	return strings.HasPrefix(data, string(DenonCommandDimmer))
+}
@@ -0,0 +1,55 @@
+package denonavr
+
+import (
+	"errors"
+	"fmt"
+)
+
+type DenonDiracFilter string
+
+const (
+	DenonDiracFilterOff   DenonDiracFilter = "OFF"
+	DenonDiracFilterSlot1 DenonDiracFilter = "SLOT1"
+	DenonDiracFilterSlot2 DenonDiracFilter = "SLOT2"
+	DenonDiracFilterSlot3 DenonDiracFilter = "SLOT3"
+)
+
+var DIRAC_FILTER_MAPPING = map[string]DenonDiracFilter{
+	"OFF":   DenonDiracFilterOff,
+	"SLOT1": DenonDiracFilterSlot1,
+	"SLOT2": DenonDiracFilterSlot2,
+	"SLOT3": DenonDiracFilterSlot3,
+}
+
+// Return the active Dirac Live filter slot
+func (d *DenonAVR) GetDiracFilter() (DenonDiracFilter, error) {
+
+	filter, err := d.GetAttribute("DiracFilter")
+	if err != nil {
+		return "", err
+	}
+
+	return DenonDiracFilter(filter.(string)), nil
+}
+
+// Select a Dirac Live filter slot or turn Dirac Live off
+// Only available with the AppCommand0300 API
+func (d *DenonAVR) SetDiracFilter(filter DenonDiracFilter) error {
+
+	if !d.isAppCommand0300Available() {
+		return errors.New("Dirac Live filter can only be set with the AppCommand0300 API")
+	}
+
+	index, ok := getAppCommand0300Index(APPCOMMAND0300_DIRAC_FILTER_MAPPING, filter)
+	if !ok {
+		return fmt.Errorf("invalid Dirac Live filter %s", filter)
+	}
+
+	if err := d.SetAppCommand0300Parameters(DenonAppCommand0300SetDirac, map[string]string{APPCOMMAND0300_PARAM_DIRAC_FILTER: index}); err != nil {
+		return err
+	}
+
+	d.SetAttribute("DiracFilter", string(filter))
+
+	return nil
+}
@@ -0,0 +1,153 @@
+package denonavr
+
+import (
+	"bufio"
+	"bytes"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	SSDP_ADDRESS           string        = "239.255.255.250:1900"
+	SSDP_DISCOVERY_TIMEOUT time.Duration = 3 * time.Second
+)
+
+// Search targets of the Denon and Marantz receivers
+var SSDP_SEARCH_TARGETS = []string{
+	"urn:schemas-upnp-org:device:MediaRenderer:1",
+	"urn:schemas-denon-com:device:AiosDevice:1",
+}
+
+// Manufacturers of the discovered devices to keep
+var SSDP_MANUFACTURERS = []string{
+	"denon",
+	"marantz",
+}
+
+// A receiver found with SSDP
+type DiscoveredDevice struct {
+	Host         string
+	FriendlyName string
+	Manufacturer string
+	ModelName    string
+}
+
+// Search Denon and Marantz receivers in the local network with SSDP M-SEARCH
+func DiscoverDevices(timeout time.Duration) ([]DiscoveredDevice, error) {
+	return discoverDevices(SSDP_ADDRESS, timeout)
+}
+
+func discoverDevices(address string, timeout time.Duration) ([]DiscoveredDevice, error) {
+
+	conn, err := net.ListenPacket("udp4", ":0")
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	destination, err := net.ResolveUDPAddr("udp4", address)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, searchTarget := range SSDP_SEARCH_TARGETS {
+		search := "M-SEARCH * HTTP/1.1\r\n" +
+			"HOST: " + SSDP_ADDRESS + "\r\n" +
+			"MAN: \"ssdp:discover\"\r\n" +
+			"MX: 2\r\n" +
+			"ST: " + searchTarget + "\r\n\r\n"
+		if _, err := conn.WriteTo([]byte(search), destination); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := conn.SetReadDeadline(time.Now().Add(timeout)); err != nil {
+		return nil, err
+	}
+
+	// Each receiver answers for several search targets, keep the description locations per host
+	var hosts []string
+	locations := make(map[string][]string)
+
+	buffer := make([]byte, 2048)
+	for {
+		n, _, err := conn.ReadFrom(buffer)
+		if err != nil {
+			// Read deadline reached
+			break
+		}
+
+		location := getSSDPLocation(buffer[:n])
+		if location == "" {
+			continue
+		}
+
+		locationURL, err := url.Parse(location)
+		if err != nil || locationURL.Hostname() == "" {
+			continue
+		}
+
+		host := locationURL.Hostname()
+		if _, ok := locations[host]; !ok {
+			hosts = append(hosts, host)
+		}
+		locations[host] = append(locations[host], location)
+	}
+
+	client := newHTTPClient(HTTP_DETECT_TIMEOUT)
+
+	var devices []DiscoveredDevice
+	for _, host := range hosts {
+		for _, location := range locations[host] {
+			description := DenonUPnPDescription{}
+			if err := getXML(client, location, &description); err != nil {
+				log.WithError(err).WithField("location", location).Debug("Cannot get UPnP description")
+				continue
+			}
+
+			if !isSSDPManufacturer(description.Manufacturer) {
+				break
+			}
+
+			devices = append(devices, DiscoveredDevice{
+				Host:         host,
+				FriendlyName: description.FriendlyName,
+				Manufacturer: description.Manufacturer,
+				ModelName:    description.ModelName,
+			})
+			break
+		}
+	}
+
+	log.WithField("devices", len(devices)).Info("Discovered Denon AVR devices")
+
+	return devices, nil
+}
+
+// Return the location of the UPnP description from a M-SEARCH response
+func getSSDPLocation(response []byte) string {
+
+	resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(response)), nil)
+	if err != nil {
+		return ""
+	}
+	defer resp.Body.Close()
+
+	return resp.Header.Get("LOCATION")
+}
+
+func isSSDPManufacturer(manufacturer string) bool {
+
+	for _, m := range SSDP_MANUFACTURERS {
+		if strings.Contains(strings.ToLower(manufacturer), m) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,168 @@
+package denonavr
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const testUPnPDescription = `<?xml version="1.0"?>
+<root xmlns="urn:schemas-upnp-org:device-1-0">
+  <device>
+    <friendlyName>%s</friendlyName>
+    <manufacturer>%s</manufacturer>
+    <modelName>%s</modelName>
+  </device>
+</root>`
+
+// Serve a UPnP description on a loopback address, each receiver needs its own host
+func newDescriptionServer(t *testing.T, ip string, friendlyName string, manufacturer string, modelName string) *httptest.Server {
+
+	listener, err := net.Listen("tcp", net.JoinHostPort(ip, "0"))
+	if err != nil {
+		t.Skipf("cannot listen on %s: %v", ip, err)
+	}
+
+	description := fmt.Sprintf(testUPnPDescription, friendlyName, manufacturer, modelName)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/xml")
+		w.Write([]byte(description))
+	}))
+	server.Listener.Close()
+	server.Listener = listener
+	server.Start()
+
+	return server
+}
+
+// Answer each M-SEARCH request with a response per location
+// Returns the address to send the requests to and the search targets received
+func newSSDPResponder(t *testing.T, locations []string) (string, func() []string) {
+
+	conn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	var mutex sync.Mutex
+	var searchTargets []string
+
+	go func() {
+		buffer := make([]byte, 2048)
+		for {
+			n, addr, err := conn.ReadFrom(buffer)
+			if err != nil {
+				return
+			}
+
+			for _, line := range strings.Split(string(buffer[:n]), "\r\n") {
+				if strings.HasPrefix(line, "ST: ") {
+					mutex.Lock()
+					searchTargets = append(searchTargets, strings.TrimPrefix(line, "ST: "))
+					mutex.Unlock()
+				}
+			}
+
+			for _, location := range locations {
+				response := "HTTP/1.1 200 OK\r\n" +
+					"CACHE-CONTROL: max-age=180\r\n" +
+					"LOCATION: " + location + "\r\n" +
+					"ST: urn:schemas-upnp-org:device:MediaRenderer:1\r\n\r\n"
+				conn.WriteTo([]byte(response), addr)
+			}
+		}
+	}()
+
+	return conn.LocalAddr().String(), func() []string {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return append([]string{}, searchTargets...)
+	}
+}
+
+func TestDiscoverDevices(t *testing.T) {
+
+	denon := newDescriptionServer(t, "127.0.0.2", "Living Room", "Denon", "AVR-X3700H")
+	defer denon.Close()
+
+	marantz := newDescriptionServer(t, "127.0.0.3", "Cinema", "Marantz", "SR6015")
+	defer marantz.Close()
+
+	other := newDescriptionServer(t, "127.0.0.4", "TV", "Other Manufacturer", "TV-1000")
+	defer other.Close()
+
+	address, getSearchTargets := newSSDPResponder(t, []string{
+		denon.URL + "/description.xml",
+		marantz.URL + "/description.xml",
+		other.URL + "/description.xml",
+		// The same receiver answers again, e.g. for another search target
+		denon.URL + "/description.xml",
+		// Responses without a valid location are ignored
+		"",
+		"not a url",
+	})
+
+	devices, err := discoverDevices(address, 500*time.Millisecond)
+	if err != nil {
+		t.Fatalf("discoverDevices returned error: %v", err)
+	}
+
+	sort.Slice(devices, func(i, j int) bool { return devices[i].Host < devices[j].Host })
+
+	want := []DiscoveredDevice{
+		{Host: "127.0.0.2", FriendlyName: "Living Room", Manufacturer: "Denon", ModelName: "AVR-X3700H"},
+		{Host: "127.0.0.3", FriendlyName: "Cinema", Manufacturer: "Marantz", ModelName: "SR6015"},
+	}
+
+	if len(devices) != len(want) {
+		t.Fatalf("discoverDevices found %d devices %+v, want %d", len(devices), devices, len(want))
+	}
+	for i := range want {
+		if devices[i] != want[i] {
+			t.Errorf("device %d = %+v, want %+v", i, devices[i], want[i])
+		}
+	}
+
+	searchTargets := getSearchTargets()
+	for _, searchTarget := range SSDP_SEARCH_TARGETS {
+		found := false
+		for _, st := range searchTargets {
+			if st == searchTarget {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("no M-SEARCH request for %s, got %v", searchTarget, searchTargets)
+		}
+	}
+}
+
+func TestGetSSDPLocation(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		response string
+		want     string
+	}{
+		{"location", "HTTP/1.1 200 OK\r\nLOCATION: http://192.168.1.10:8080/description.xml\r\n\r\n", "http://192.168.1.10:8080/description.xml"},
+		{"lower case header", "HTTP/1.1 200 OK\r\nLocation: http://192.168.1.10:60006/upnp/desc/aios_device/aios_device.xml\r\n\r\n", "http://192.168.1.10:60006/upnp/desc/aios_device/aios_device.xml"},
+		{"no location", "HTTP/1.1 200 OK\r\nST: upnp:rootdevice\r\n\r\n", ""},
+		{"no http response", "NOTIFY * HTTP/1.1\r\nHOST: 239.255.255.250:1900\r\n\r\n", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getSSDPLocation([]byte(tt.response)); got != tt.want {
+				t.Errorf("getSSDPLocation() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package denonavr
+
+import (
+	"fmt"
+	"strings"
+)
+
+type DenonECOMode string
+
+const (
+	DenonECOModeOn   DenonECOMode = "ON"
+	DenonECOModeAuto DenonECOMode = "AUTO"
+	DenonECOModeOff  DenonECOMode = "OFF"
+)
+
+var ECO_MODES = []DenonECOMode{
+	DenonECOModeOn,
+	DenonECOModeAuto,
+	DenonECOModeOff,
+}
+
+// Publish the ECO mode of the main zone data
+// Not all models support ECO mode, the value is empty then
+func (d *DenonAVR) updateECOModeAndNotify() {
+
+	ecoMode := d.getMainZoneData().ECOMode
+	if ecoMode == "" {
+		return
+	}
+
+	d.SetAttribute("ECOMode", strings.ToUpper(ecoMode))
+}
+
+// ECOON, ECOAUTO, ECOOFF
+func (d *DenonAVR) handleECOTelnetEvent(param string) {
+	d.SetAttribute("ECOMode", param)
+}
+
+func (d *DenonAVR) GetECOMode() (DenonECOMode, error) {
+
+	ecoMode, err := d.GetAttribute("ECOMode")
+	if err != nil {
+		return "", err
+	}
+
+	return DenonECOMode(ecoMode.(string)), nil
+}
+
+// Set the ECO mode to on, auto or off
+// ECOON, ECOAUTO, ECOOFF
+func (d *DenonAVR) SetECOMode(ecoMode DenonECOMode) error {
+
+	for _, m := range ECO_MODES {
+		if m == ecoMode {
+			_, err := d.sendCommandToDevice(DenonCommandECO, string(ecoMode))
+			return err
+		}
+	}
+
+	return fmt.Errorf("invalid ECO mode %s", ecoMode)
+}
+
+// Check if a telnet event is a ECO mode event
+func isTelnetECOEvent(data string) bool {
+	return strings.HasPrefix(data, string(DenonCommandECO))
+}
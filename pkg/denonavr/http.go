@@ -0,0 +1,76 @@
+package denonavr
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Ports and schemes receivers serve the control API on, in the order they are tried
+// Receivers from 2016 onwards use port 8080 and HTTPS on port 10443 with a self-signed certificate
+var HTTP_BASE_URL_CANDIDATES = []struct {
+	Scheme string
+	Port   string
+}{
+	{"http", "80"},
+	{"http", "8080"},
+	{"https", "10443"},
+}
+
+const HTTP_DETECT_TIMEOUT = 3 * time.Second
+
+// A receiver not answering must not block the updates and commands
+const HTTP_TIMEOUT = 5 * time.Second
+
+// HTTP client accepting the self-signed certificate of the receivers
+func newHTTPClient(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true}, // The receivers use a self-signed certificate
+		},
+	}
+}
+
+// Return the scheme, host and port the receiver serves the control API on, e.g. http://192.168.1.10:8080
+// If the host contains a port, only this port is tried with HTTP and HTTPS
+func DetectBaseURL(host string) (string, error) {
+
+	var candidates []string
+
+	if _, port, err := net.SplitHostPort(host); err == nil {
+		candidates = []string{"http://" + host, "https://" + host}
+		log.WithField("port", port).Debug("Use port of the host to detect the base url")
+	} else {
+		for _, candidate := range HTTP_BASE_URL_CANDIDATES {
+			candidates = append(candidates, candidate.Scheme+"://"+net.JoinHostPort(host, candidate.Port))
+		}
+	}
+
+	client := newHTTPClient(HTTP_DETECT_TIMEOUT)
+
+	for _, baseURL := range candidates {
+		resp, err := client.Get(baseURL + MAINZONE_URL)
+		if err != nil {
+			log.WithError(err).WithField("url", baseURL).Debug("Control API not available")
+			continue
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode == http.StatusOK {
+			log.WithField("url", baseURL).Info("Detected control API of Denon AVR")
+			return baseURL, nil
+		}
+	}
+
+	return "", fmt.Errorf("no control API found on %s", host)
+}
+
+// Return the url of a path on the receiver
+func (d *DenonAVR) getURL(path string) string {
+	return d.BaseURL + path
+}
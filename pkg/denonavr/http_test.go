@@ -0,0 +1,156 @@
+package denonavr
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// Serve the main zone status like a receiver, with HTTPS like the receivers from 2016 onwards
+func newControlAPIServer(status int, useTLS bool) *httptest.Server {
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != MAINZONE_URL {
+			http.NotFound(w, r)
+			return
+		}
+		w.WriteHeader(status)
+	})
+
+	if useTLS {
+		return httptest.NewTLSServer(handler)
+	}
+
+	return httptest.NewServer(handler)
+}
+
+func TestDetectBaseURLWithPort(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		tls     bool
+		status  int
+		wantErr bool
+	}{
+		{"http", false, http.StatusOK, false},
+		{"https", true, http.StatusOK, false},
+		{"no control API", false, http.StatusNotFound, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			server := newControlAPIServer(tt.status, tt.tls)
+			defer server.Close()
+
+			host := server.Listener.Addr().String()
+
+			baseURL, err := DetectBaseURL(host)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("DetectBaseURL(%s) = %s, want error", host, baseURL)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DetectBaseURL(%s) returned error: %v", host, err)
+			}
+			if baseURL != server.URL {
+				t.Errorf("DetectBaseURL(%s) = %s, want %s", host, baseURL, server.URL)
+			}
+		})
+	}
+}
+
+func TestDetectBaseURLCandidates(t *testing.T) {
+
+	server := newControlAPIServer(http.StatusOK, false)
+	defer server.Close()
+
+	// A port nothing listens on, the detection must continue with the next candidate
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, closedPort, _ := net.SplitHostPort(listener.Addr().String())
+	listener.Close()
+
+	_, serverPort, _ := net.SplitHostPort(server.Listener.Addr().String())
+
+	candidates := HTTP_BASE_URL_CANDIDATES
+	defer func() { HTTP_BASE_URL_CANDIDATES = candidates }()
+	HTTP_BASE_URL_CANDIDATES = []struct {
+		Scheme string
+		Port   string
+	}{
+		{"http", closedPort},
+		{"http", serverPort},
+	}
+
+	baseURL, err := DetectBaseURL("127.0.0.1")
+	if err != nil {
+		t.Fatalf("DetectBaseURL returned error: %v", err)
+	}
+	if baseURL != server.URL {
+		t.Errorf("DetectBaseURL = %s, want %s", baseURL, server.URL)
+	}
+}
+
+func TestGetURL(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		baseURL string
+		path    string
+		want    string
+	}{
+		{"default", "http://192.168.1.10", MAINZONE_URL, "http://192.168.1.10" + MAINZONE_URL},
+		{"port", "http://192.168.1.10:8080", DEVICEINFO_URL, "http://192.168.1.10:8080" + DEVICEINFO_URL},
+		{"https", "https://192.168.1.10:10443", APPCOMMAND0300_URL, "https://192.168.1.10:10443" + APPCOMMAND0300_URL},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewDenonAVR("192.168.1.10", false, false)
+			d.BaseURL = tt.baseURL
+
+			if got := d.getURL(tt.path); got != tt.want {
+				t.Errorf("getURL(%s) = %s, want %s", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+// The requests of a receiver use the detected base url
+func TestGetURLWithDetectedBaseURL(t *testing.T) {
+
+	server := newControlAPIServer(http.StatusOK, true)
+	defer server.Close()
+
+	d := NewDenonAVR(server.Listener.Addr().String(), false, false)
+
+	baseURL, err := DetectBaseURL(d.Host)
+	if err != nil {
+		t.Fatalf("DetectBaseURL returned error: %v", err)
+	}
+	d.BaseURL = baseURL
+
+	u, err := url.Parse(d.getURL(MAINZONE_URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Scheme != "https" || u.Path != MAINZONE_URL {
+		t.Errorf("getURL(%s) = %s, want https url with path %s", MAINZONE_URL, u, MAINZONE_URL)
+	}
+
+	resp, err := d.httpClient.Get(d.getURL(MAINZONE_URL))
+	if err != nil {
+		t.Fatalf("request to %s failed: %v", d.getURL(MAINZONE_URL), err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("request to %s returned status code %d", d.getURL(MAINZONE_URL), resp.StatusCode)
+	}
+}
@@ -7,30 +7,34 @@ import (
 	"k8s.io/utils/strings/slices"
 )
 
-func (d *DenonAVR) Play() int {
-	status, _ := d.sendCommandToDevice(DenonCommandNS, "9A")
+func (d *DenonAVR) Play() DenonCommandResult {
+	result, _ := d.sendCommandToDevice(DenonCommandNS, "9A")
 
-	return status
+	return result
 }
 
-func (d *DenonAVR) Pause() int {
-	status, _ := d.sendCommandToDevice(DenonCommandNS, "9B")
+func (d *DenonAVR) Pause() DenonCommandResult {
+	result, _ := d.sendCommandToDevice(DenonCommandNS, "9B")
 
-	return status
+	return result
 }
 
 // Get the current Media Title
-// Title of the Playing media or the current Input Function
+// Title of the Playing media, the tuner station or the current Input Function
 func (d *DenonAVR) getMediaTitle() string {
 	media_title := ""
+	inputFuncSelect := d.getMainZoneData().InputFuncSelect
 
-	if d.IsOn() {
-		if slices.Contains(PLAYING_SOURCES, d.mainZoneData.InputFuncSelect) {
+	if d.IsOn(MainZone) {
+		if slices.Contains(TUNER_SOURCES, inputFuncSelect) && d.getTunerTitle() != "" {
+			// The tuner station name or frequency
+			media_title = d.getTunerTitle()
+		} else if netAudioStatus := d.getLastNetAudioStatus(); slices.Contains(PLAYING_SOURCES, inputFuncSelect) && len(netAudioStatus.SzLine) > 1 {
 			// This is a source that is playing audio
-			media_title = d.netAudioStatus.SzLine[1]
+			media_title = netAudioStatus.SzLine[1]
 		} else {
 			// Not a playing source
-			media_title = d.mainZoneData.InputFuncSelect
+			media_title = inputFuncSelect
 		}
 	}
 
@@ -43,16 +47,16 @@ func (d *DenonAVR) getMediaTitle() string {
 func (d *DenonAVR) getMediaImageURL() string {
 	media_image_url := ""
 
-	if d.IsOn() {
-		if slices.Contains(PLAYING_SOURCES, d.mainZoneData.InputFuncSelect) {
+	if d.IsOn(MainZone) {
+		if slices.Contains(PLAYING_SOURCES, d.getMainZoneData().InputFuncSelect) {
 			// This is a source that is playing audio
 			// fot the moment, also set this to the input func
 
 			hash := fnv.New32a()
 			hash.Write([]byte(d.getMediaTitle()))
-			media_image_url = fmt.Sprintf("http://%s:%d/NetAudio/art.asp-jpg?%d", d.Host, 80, hash.Sum32())
+			media_image_url = fmt.Sprintf("%s/NetAudio/art.asp-jpg?%d", d.BaseURL, hash.Sum32())
 		} else {
-			media_image_url = fmt.Sprintf("http://%s:%d/", d.Host, 80) + "img/album%20art_S.png"
+			media_image_url = d.getURL("/img/album%20art_S.png")
 		}
 	}
 
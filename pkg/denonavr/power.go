@@ -4,43 +4,49 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
-func (d *DenonAVR) TurnOn() error {
-	if _, err := d.sendCommandToDevice(DenonCommandPower, "ON"); err != nil {
-		return err
+func (d *DenonAVR) TurnOn(zone DenonZone) error {
+	if zone == MainZone {
+		if _, err := d.sendCommandToDevice(DenonCommandPower, "ON"); err != nil {
+			return err
+		}
 	}
-	_, err := d.sendCommandToDevice(DennonCommandZoneMain, "ON")
+	_, err := d.sendCommandToDevice(d.getZoneCommand(zone), "ON")
 
 	return err
 }
 
-func (d *DenonAVR) TurnOff() error {
+func (d *DenonAVR) TurnOff(zone DenonZone) error {
 
-	if _, err := d.sendCommandToDevice(DenonCommandPower, "STANDBY"); err != nil {
-		return err
+	if zone == MainZone {
+		if _, err := d.sendCommandToDevice(DenonCommandPower, "STANDBY"); err != nil {
+			return err
+		}
 	}
-	_, err := d.sendCommandToDevice(DennonCommandZoneMain, "OFF")
+	_, err := d.sendCommandToDevice(d.getZoneCommand(zone), "OFF")
 
 	return err
 }
 
-func (d *DenonAVR) TogglePower() error {
+func (d *DenonAVR) TogglePower(zone DenonZone) error {
 
-	if d.IsOn() {
-		return d.TurnOff()
+	if d.IsOn(zone) {
+		return d.TurnOff(zone)
 	}
 
-	return d.TurnOn()
+	return d.TurnOn(zone)
 }
 
-func (d *DenonAVR) IsOn() bool {
+func (d *DenonAVR) IsOn(zone DenonZone) bool {
 
-	mainzonepower, err := d.GetAttribute("MainZonePower")
+	attribute := d.GetZoneName(zone) + "Power"
+
+	zonepower, err := d.GetAttribute(attribute)
 	if err != nil {
-		log.WithError(err).Error("MainZonePower attribute not found")
+		log.WithError(err).Errorf("%s attribute not found", attribute)
 		return false
 	}
 
-	switch mainzonepower.(string) {
+	switch zonepower.(string) {
 	case "ON":
 		return true
 	default:
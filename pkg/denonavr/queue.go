@@ -0,0 +1,173 @@
+package denonavr
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// The Denon control protocol needs about 50ms between commands
+// Commands sent faster are silently dropped by the device
+const COMMAND_INTERVAL = 50 * time.Millisecond
+
+type queuedCommand struct {
+	cmd     DenonCommand
+	payload string
+	send    func(DenonCommand, string) (DenonCommandResult, error)
+	results []chan queuedCommandResult
+	// Only sent when no other command is queued
+	lowPriority bool
+}
+
+type queuedCommandResult struct {
+	result DenonCommandResult
+	err    error
+}
+
+// Serialized command queue to send commands to the device with the required pacing
+// Power and mute commands are sent before all other queued commands
+// Low priority commands, e.g. status queries, are only sent when no other command is queued
+// Repeated volume up/down commands not yet sent are coalesced into one
+type commandQueue struct {
+	interval time.Duration
+
+	mutex    sync.Mutex
+	priority []*queuedCommand
+	normal   []*queuedCommand
+	low      []*queuedCommand
+	running  bool
+	lastSent time.Time
+}
+
+func newCommandQueue(interval time.Duration) *commandQueue {
+	return &commandQueue{
+		interval: interval,
+	}
+}
+
+// Queue a command and wait until it is sent to the device
+// The send function is called with the command and payload when it's the commands turn
+func (q *commandQueue) sendCommand(cmd DenonCommand, payload string, send func(DenonCommand, string) (DenonCommandResult, error)) (DenonCommandResult, error) {
+	return q.queueCommand(cmd, payload, send, false)
+}
+
+// Queue a command with low priority and wait until it is sent to the device
+// User commands queued in the meantime are sent first
+func (q *commandQueue) sendLowPriorityCommand(cmd DenonCommand, payload string, send func(DenonCommand, string) (DenonCommandResult, error)) (DenonCommandResult, error) {
+	return q.queueCommand(cmd, payload, send, true)
+}
+
+func (q *commandQueue) queueCommand(cmd DenonCommand, payload string, send func(DenonCommand, string) (DenonCommandResult, error), lowPriority bool) (DenonCommandResult, error) {
+
+	result := make(chan queuedCommandResult, 1)
+
+	q.enqueue(&queuedCommand{
+		cmd:         cmd,
+		payload:     payload,
+		send:        send,
+		results:     []chan queuedCommandResult{result},
+		lowPriority: lowPriority,
+	})
+
+	r := <-result
+	return r.result, r.err
+}
+
+func (q *commandQueue) enqueue(command *queuedCommand) {
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if isCoalescableCommand(command.cmd, command.payload) {
+		for _, queued := range q.normal {
+			if queued.cmd == command.cmd && queued.payload == command.payload {
+				log.WithFields(log.Fields{
+					"cmd":     string(command.cmd),
+					"payload": command.payload,
+				}).Debug("Coalesce command with already queued command")
+				queued.results = append(queued.results, command.results...)
+				return
+			}
+		}
+	}
+
+	switch {
+	case command.lowPriority:
+		q.low = append(q.low, command)
+	case isPriorityCommand(command.cmd, command.payload):
+		q.priority = append(q.priority, command)
+	default:
+		q.normal = append(q.normal, command)
+	}
+
+	// Only run the worker while there are commands in the queue
+	if !q.running {
+		q.running = true
+		go q.run()
+	}
+}
+
+func (q *commandQueue) run() {
+
+	for {
+		// Wait until the device accepts the next command
+		q.mutex.Lock()
+		wait := q.interval - time.Since(q.lastSent)
+		q.mutex.Unlock()
+		if wait > 0 {
+			time.Sleep(wait)
+		}
+
+		q.mutex.Lock()
+		var command *queuedCommand
+		switch {
+		case len(q.priority) > 0:
+			command, q.priority = q.priority[0], q.priority[1:]
+		case len(q.normal) > 0:
+			command, q.normal = q.normal[0], q.normal[1:]
+		case len(q.low) > 0:
+			command, q.low = q.low[0], q.low[1:]
+		default:
+			q.running = false
+			q.mutex.Unlock()
+			return
+		}
+		q.mutex.Unlock()
+
+		commandResult, err := command.send(command.cmd, command.payload)
+
+		q.mutex.Lock()
+		q.lastSent = time.Now()
+		q.mutex.Unlock()
+
+		for _, result := range command.results {
+			result <- queuedCommandResult{result: commandResult, err: err}
+		}
+	}
+}
+
+// Power and mute commands are sent before all other commands
+func isPriorityCommand(cmd DenonCommand, payload string) bool {
+
+	switch cmd {
+	case DenonCommandPower, DennonCommandZoneMain, DenonCommandMainZoneMute:
+		return true
+	case DenonCommandZone2, DenonCommandZone3:
+		return payload == "ON" || payload == "OFF" || strings.HasPrefix(payload, string(DenonCommandMainZoneMute))
+	}
+
+	return false
+}
+
+// Repeated volume up/down commands can be coalesced, e.g. when volume up is held on the remote
+func isCoalescableCommand(cmd DenonCommand, payload string) bool {
+
+	switch cmd {
+	case DenonCommandMainZoneVolume, DenonCommandZone2, DenonCommandZone3:
+		return payload == "UP" || payload == "DOWN"
+	}
+
+	return false
+}
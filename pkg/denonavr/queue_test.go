@@ -0,0 +1,180 @@
+package denonavr
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// Records the commands in the order the queue sends them
+type fakeCommandWriter struct {
+	mutex sync.Mutex
+	sent  []string
+	times []time.Time
+}
+
+func (w *fakeCommandWriter) send(cmd DenonCommand, payload string) (DenonCommandResult, error) {
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.sent = append(w.sent, string(cmd)+payload)
+	w.times = append(w.times, time.Now())
+
+	return DenonCommandResultAccepted, nil
+}
+
+func (w *fakeCommandWriter) getSent() []string {
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	return append([]string{}, w.sent...)
+}
+
+// Wait until the callers of the queued commands are registered
+func waitForQueuedCallers(t *testing.T, q *commandQueue, callers int) {
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		q.mutex.Lock()
+		queued := 0
+		for _, commands := range [][]*queuedCommand{q.priority, q.normal, q.low} {
+			for _, command := range commands {
+				queued += len(command.results)
+			}
+		}
+		q.mutex.Unlock()
+
+		if queued == callers {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	t.Fatalf("%d callers not queued", callers)
+}
+
+func TestCommandQueueOrder(t *testing.T) {
+
+	type command struct {
+		cmd         DenonCommand
+		payload     string
+		lowPriority bool
+	}
+
+	tests := []struct {
+		name     string
+		commands []command
+		want     []string
+	}{
+		{
+			name:     "power and mute first",
+			commands: []command{{DenonCommandMainZoneVolume, "50", false}, {DenonCommandPower, "ON", false}, {DenonCommandMainZoneMute, "ON", false}},
+			want:     []string{"PWON", "MUON", "MV50"},
+		},
+		{
+			name:     "zone power and mute first",
+			commands: []command{{DenonCommandSelectInput, "CD", false}, {DenonCommandZone2, "ON", false}, {DenonCommandZone3, "MUOFF", false}, {DennonCommandZoneMain, "OFF", false}},
+			want:     []string{"Z2ON", "Z3MUOFF", "ZMOFF", "SICD"},
+		},
+		{
+			name:     "low priority last",
+			commands: []command{{DenonCommandSelectInput, "?", true}, {DenonCommandMainZoneVolume, "50", false}, {DenonCommandPS, "DYNEQ ?", true}, {DenonCommandPower, "ON", false}},
+			want:     []string{"PWON", "MV50", "SI?", "PSDYNEQ ?"},
+		},
+		{
+			name:     "coalesce volume up and down",
+			commands: []command{{DenonCommandMainZoneVolume, "UP", false}, {DenonCommandMainZoneVolume, "UP", false}, {DenonCommandMainZoneVolume, "DOWN", false}, {DenonCommandMainZoneVolume, "UP", false}, {DenonCommandMainZoneVolume, "DOWN", false}},
+			want:     []string{"MVUP", "MVDOWN"},
+		},
+		{
+			name:     "coalesce zone volume",
+			commands: []command{{DenonCommandZone2, "UP", false}, {DenonCommandZone3, "UP", false}, {DenonCommandZone2, "UP", false}},
+			want:     []string{"Z2UP", "Z3UP"},
+		},
+		{
+			name:     "absolute volume not coalesced",
+			commands: []command{{DenonCommandMainZoneVolume, "50", false}, {DenonCommandMainZoneVolume, "50", false}},
+			want:     []string{"MV50", "MV50"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			writer := &fakeCommandWriter{}
+			q := newCommandQueue(time.Millisecond)
+
+			// The first command keeps the queue busy until all test commands are queued
+			sending := make(chan struct{})
+			release := make(chan struct{})
+			blocked := make(chan struct{})
+			go func() {
+				q.sendCommand(DenonCommandNS, "BLOCK", func(cmd DenonCommand, payload string) (DenonCommandResult, error) {
+					close(sending)
+					<-release
+					return DenonCommandResultAccepted, nil
+				})
+				close(blocked)
+			}()
+			<-sending
+
+			var wg sync.WaitGroup
+			for i, c := range tt.commands {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					result, err := q.queueCommand(c.cmd, c.payload, writer.send, c.lowPriority)
+					if err != nil || result != DenonCommandResultAccepted {
+						t.Errorf("command %s%s returned %s, %v", c.cmd, c.payload, result, err)
+					}
+				}()
+				waitForQueuedCallers(t, q, i+1)
+			}
+
+			close(release)
+			<-blocked
+			wg.Wait()
+
+			sent := writer.getSent()
+			if len(sent) != len(tt.want) {
+				t.Fatalf("sent %v, want %v", sent, tt.want)
+			}
+			for i, want := range tt.want {
+				if sent[i] != want {
+					t.Errorf("sent %v, want %v", sent, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestCommandQueueInterval(t *testing.T) {
+
+	writer := &fakeCommandWriter{}
+	q := newCommandQueue(COMMAND_INTERVAL)
+
+	var wg sync.WaitGroup
+	for _, payload := range []string{"10", "20", "30", "40"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			q.sendCommand(DenonCommandMainZoneVolume, payload, writer.send)
+		}()
+	}
+	wg.Wait()
+
+	writer.mutex.Lock()
+	defer writer.mutex.Unlock()
+
+	if len(writer.times) != 4 {
+		t.Fatalf("sent %d commands, want 4", len(writer.times))
+	}
+	for i := 1; i < len(writer.times); i++ {
+		if gap := writer.times[i].Sub(writer.times[i-1]); gap < COMMAND_INTERVAL {
+			t.Errorf("command %d sent %s after the previous one, want at least %s", i, gap, COMMAND_INTERVAL)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package denonavr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// The receiver stores five quick select presets with input, volume and sound mode
+const QUICK_SELECT_SLOTS int = 5
+
+// Return the name of the quick select in the MS command
+// Quick select on Denon, smart select on Marantz receivers
+// The name reported by the receiver is used first, then the brand from HTTP or the device info
+func (d *DenonAVR) getQuickSelectPrefix() string {
+
+	if prefix, err := d.GetAttribute("QuickSelectPrefix"); err == nil {
+		return prefix.(string)
+	}
+
+	if strings.Contains(strings.ToUpper(d.getMainZoneData().BrandId), "MARANTZ") {
+		return "SMART"
+	}
+
+	if deviceInfo := d.GetDeviceInfo(); deviceInfo != nil && deviceInfo.IsMarantz() {
+		return "SMART"
+	}
+
+	return "QUICK"
+}
+
+// Check if the param of a MS event is a quick select and not a surround mode
+func isTelnetQuickSelect(param string) bool {
+	return strings.HasPrefix(param, "QUICK") || strings.HasPrefix(param, "SMART")
+}
+
+// Handle the active quick select slot, 0 if no quick select is active
+// MSQUICK1, MSSMART2, MSQUICK0, MSQUICK3 MEMORY
+func (d *DenonAVR) handleQuickSelectTelnetEvent(param string) {
+
+	// Only the name the receiver supports is answered, remember it for the commands
+	if strings.HasPrefix(param, "SMART") {
+		d.SetAttribute("QuickSelectPrefix", "SMART")
+	} else {
+		d.SetAttribute("QuickSelectPrefix", "QUICK")
+	}
+
+	param = strings.TrimPrefix(strings.TrimPrefix(param, "QUICK"), "SMART")
+	if slot, _, found := strings.Cut(param, " "); found {
+		// Stored a quick select, the stored slot is now active
+		param = slot
+	}
+
+	slot, err := strconv.Atoi(param)
+	if err != nil {
+		log.WithError(err).Debug("failed to parse quick select slot")
+		return
+	}
+
+	d.SetAttribute("QuickSelect", slot)
+}
+
+// Return the active quick select slot, 0 if no quick select is active
+func (d *DenonAVR) GetQuickSelect() (int, error) {
+
+	slot, err := d.GetAttribute("QuickSelect")
+	if err != nil {
+		return 0, err
+	}
+
+	return slot.(int), nil
+}
+
+// Recall a quick select slot from 1 to 5, e.g. MSQUICK1
+func (d *DenonAVR) RecallQuickSelect(slot int) error {
+
+	if slot < 1 || slot > QUICK_SELECT_SLOTS {
+		return fmt.Errorf("invalid quick select slot %d", slot)
+	}
+
+	_, err := d.sendCommandToDevice(DenonCommandMS, d.getQuickSelectPrefix()+strconv.Itoa(slot))
+	return err
+}
+
+// Store the current input, volume and sound mode in a quick select slot, e.g. MSQUICK1 MEMORY
+func (d *DenonAVR) StoreQuickSelect(slot int) error {
+
+	if slot < 1 || slot > QUICK_SELECT_SLOTS {
+		return fmt.Errorf("invalid quick select slot %d", slot)
+	}
+
+	_, err := d.sendCommandToDevice(DenonCommandMS, d.getQuickSelectPrefix()+strconv.Itoa(slot)+" MEMORY")
+	return err
+}
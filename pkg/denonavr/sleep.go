@@ -0,0 +1,81 @@
+package denonavr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// The sleep timer can be set from 1 to 120 minutes
+const (
+	SLEEP_TIMER_MIN int = 1
+	SLEEP_TIMER_MAX int = 120
+)
+
+// Return the command and payload prefix to control the sleep timer of a zone
+// SLP for the main zone, Z2SLP and Z3SLP for the other zones
+func (d *DenonAVR) getSleepCommand(zone DenonZone) (DenonCommand, string) {
+
+	if zone == MainZone {
+		return DenonCommandSleep, ""
+	}
+
+	return d.getZoneCommand(zone), string(DenonCommandSleep)
+}
+
+// Handle the sleep timer events, the param is OFF or the remaining minutes
+// SLPOFF, SLP060, Z2SLP120
+func (d *DenonAVR) handleSleepTelnetEvent(zone DenonZone, param string) {
+
+	zoneName := d.GetZoneName(zone)
+
+	if param == "OFF" {
+		d.SetAttribute(zoneName+"Sleep", 0)
+		return
+	}
+
+	minutes, err := strconv.Atoi(param)
+	if err != nil {
+		log.WithError(err).WithField("zone", zoneName).Error("failed to parse sleep timer")
+		return
+	}
+
+	d.SetAttribute(zoneName+"Sleep", minutes)
+}
+
+// Return the remaining minutes of the sleep timer, 0 if the sleep timer is off
+func (d *DenonAVR) GetSleepTimer(zone DenonZone) (int, error) {
+
+	minutes, err := d.GetAttribute(d.GetZoneName(zone) + "Sleep")
+	if err != nil {
+		return 0, err
+	}
+
+	return minutes.(int), nil
+}
+
+// Set the sleep timer of a zone in minutes, e.g. SLP030 or Z2SLP030
+func (d *DenonAVR) SetSleepTimer(zone DenonZone, minutes int) error {
+
+	if minutes < SLEEP_TIMER_MIN || minutes > SLEEP_TIMER_MAX {
+		return fmt.Errorf("sleep timer %d out of range", minutes)
+	}
+
+	cmd, prefix := d.getSleepCommand(zone)
+	_, err := d.sendCommandToDevice(cmd, prefix+fmt.Sprintf("%03d", minutes))
+	return err
+}
+
+func (d *DenonAVR) SleepTimerOff(zone DenonZone) error {
+
+	cmd, prefix := d.getSleepCommand(zone)
+	_, err := d.sendCommandToDevice(cmd, prefix+"OFF")
+	return err
+}
+
+// Check if a telnet event is a sleep timer event
+func isTelnetSleepEvent(data string) bool {
+	return strings.HasPrefix(data, string(DenonCommandSleep))
+}
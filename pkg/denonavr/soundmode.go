@@ -111,11 +111,29 @@ var SOUND_MODE_MAPPING = map[string][]string{
 	ALL_ZONE_STEREO: {"ALL ZONE STEREO"},
 }
 
-func (d *DenonAVR) GetSoundModeList() []string {
+// Zone2 and Zone3 have no surround modes, only the channel setting
+var ZONE_SOUND_MODE_MAPPING = map[string]string{
+	"STEREO": "ST",
+	"MONO":   "MONO",
+}
+
+func (d *DenonAVR) GetSoundModeList(zone DenonZone) []string {
 
 	var soundModeList []string
 
+	if zone != MainZone {
+		for mode := range ZONE_SOUND_MODE_MAPPING {
+			soundModeList = append(soundModeList, mode)
+		}
+		return soundModeList
+	}
+
+	deviceInfo := d.GetDeviceInfo()
 	for mode := range SOUND_MODE_MAPPING {
+		// Only the sound modes the receiver supports, if known
+		if deviceInfo != nil && !deviceInfo.SupportsSoundMode(mode) {
+			continue
+		}
 		soundModeList = append(soundModeList, mode)
 	}
 
@@ -124,14 +142,33 @@ func (d *DenonAVR) GetSoundModeList() []string {
 
 func (d *DenonAVR) GetSurroundMode(zone DenonZone) string {
 
-	return d.getZoneSurroundMode(d.zoneStatus[zone])
+	return d.getZoneSurroundMode(d.getLastZoneStatus(zone))
 }
 
 func (d *DenonAVR) getZoneSurroundMode(zoneStatus DenonZoneStatus) string {
 
+	return d.getSurroundModeCategory(zoneStatus.SurrMode)
+}
+
+// Return the sound mode from the SOUND_MODE_MAPPING for a surround mode reported by the device
+// The surround mode is either part of the HTTP zone status or a MS telnet event
+func (d *DenonAVR) getSurroundModeCategory(rawSurroundMode string) string {
+
 	var surroundMode string
 
-	rawSurroundMode := strings.TrimRight(zoneStatus.SurrMode, " ")
+	rawSurroundMode = strings.TrimRight(rawSurroundMode, " ")
+
+	// Direct match with a sound mode or one of its surround modes
+	for mode, surroundModes := range SOUND_MODE_MAPPING {
+		if strings.EqualFold(mode, rawSurroundMode) {
+			return mode
+		}
+		for _, s := range surroundModes {
+			if strings.EqualFold(s, rawSurroundMode) {
+				return mode
+			}
+		}
+	}
 
 	if strings.Contains(strings.ToUpper(rawSurroundMode), "DTS") {
 		surroundMode = "DTS SURROUND"
@@ -161,14 +198,6 @@ func (d *DenonAVR) getZoneSurroundMode(zoneStatus DenonZoneStatus) string {
 		surroundMode = "AURO2DSURR"
 	}
 
-	if strings.ToUpper(rawSurroundMode) == "None" {
-		surroundMode = "AUTO"
-	}
-
-	if strings.ToUpper(rawSurroundMode) == "None2" {
-		surroundMode = "STANDARD"
-	}
-
 	if surroundMode == "" {
 		surroundMode = rawSurroundMode
 	}
@@ -176,9 +205,19 @@ func (d *DenonAVR) getZoneSurroundMode(zoneStatus DenonZoneStatus) string {
 	return surroundMode
 }
 
-func (d *DenonAVR) SetSoundModeMainZone(mode string) int {
+func (d *DenonAVR) SetSoundMode(zone DenonZone, mode string) DenonCommandResult {
+
+	if zone != MainZone {
+		// Z2CSST, Z2CSMONO
+		channelSetting, ok := ZONE_SOUND_MODE_MAPPING[strings.ToUpper(mode)]
+		if !ok {
+			return DenonCommandResultRejected
+		}
+		result, _ := d.sendCommandToDevice(d.getZoneCommand(zone), "CS"+channelSetting)
+		return result
+	}
 
-	status, _ := d.sendCommandToDevice(DenonCommandMS, strings.ToUpper(mode))
-	return status
+	result, _ := d.sendCommandToDevice(DenonCommandMS, strings.ToUpper(mode))
+	return result
 
 }
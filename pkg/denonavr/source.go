@@ -107,24 +107,92 @@ func (d *DenonAVR) GetZoneInputFuncList(zone DenonZone) map[string]string {
 
 	// Only add those not deleted
 	// Use renamed value
-	for i, input := range d.zoneStatus[zone].InputFuncList {
+	zoneStatus := d.getLastZoneStatus(zone)
+	for i, input := range zoneStatus.InputFuncList {
 		// only the ones active or empty (== Online Music)
-		if d.zoneStatus[zone].SourceDelete[i] == "USE" || d.zoneStatus[zone].SourceDelete[i] == "" {
-			inputFuncList[input] = strings.TrimRight(d.zoneStatus[zone].RenameSource[i], " ")
+		if zoneStatus.SourceDelete[i] == "USE" || zoneStatus.SourceDelete[i] == "" {
+			inputFuncList[input] = strings.TrimRight(zoneStatus.RenameSource[i], " ")
 		}
 	}
 
-	return inputFuncList
+	return d.filterSupportedInputFunctions(zone, inputFuncList)
 
 }
 
-func (d *DenonAVR) SetSelectSourceMainZone(source string) int {
+// Return only the input functions the receiver reports in its device info
+// Network sources are not part of the device info and always kept
+// If no input function is found, the device info uses other names and the list is not filtered
+func (d *DenonAVR) filterSupportedInputFunctions(zone DenonZone, inputFuncList map[string]string) map[string]string {
 
-	inputFuncList := d.GetZoneInputFuncList(MainZone)
+	deviceInfo := d.GetDeviceInfo()
+	if deviceInfo == nil {
+		return inputFuncList
+	}
+
+	supportedInputFuncList := make(map[string]string)
+	found := false
+	for input, renamedInput := range inputFuncList {
+		if deviceInfo.SupportsInputFunction(zone, input) {
+			supportedInputFuncList[input] = renamedInput
+			found = true
+		} else if slices.Contains(NETAUDIO_SOURCES, input) {
+			supportedInputFuncList[input] = renamedInput
+		}
+	}
+
+	if !found {
+		return inputFuncList
+	}
+
+	return supportedInputFuncList
+}
+
+// Return the name of an input function as shown in the source list of a zone
+// First rename with the SOURCE_MAPPING, then with the custom renames from the device
+func (d *DenonAVR) getRenamedInputFuncSelect(zone DenonZone, inputFuncSelect string) string {
+
+	inputFuncSelectList := d.GetZoneInputFuncList(zone)
+
+	renamedInputFuncSelect := inputFuncSelect
+	// Rename Source with the SOURCE_MAPPING if necessary
+	for source, origin := range SOURCE_MAPPING {
+		if origin == inputFuncSelect {
+			renamedInputFuncSelect = source
+			break
+		}
+	}
+	// And then custom renames
+	if inputFuncSelectList[renamedInputFuncSelect] != "" {
+		renamedInputFuncSelect = inputFuncSelectList[renamedInputFuncSelect]
+	}
+
+	return renamedInputFuncSelect
+}
+
+// Return the name of an input function from a telnet event as shown in the source list of a zone
+// Telnet uses different names for some sources, e.g. IRADIO instead of Internet Radio
+func (d *DenonAVR) getRenamedTelnetSource(zone DenonZone, source string) string {
+
+	if TELNET_MAPPING[source] != "" {
+		source = TELNET_MAPPING[source]
+	}
+
+	return d.getRenamedInputFuncSelect(zone, source)
+}
+
+// Check if a telnet event contains a input function
+func isTelnetSource(source string) bool {
+	return slices.Contains(TELNET_SOURCES, source) || TELNET_MAPPING[source] != ""
+}
+
+func (d *DenonAVR) SetSelectSource(zone DenonZone, source string) DenonCommandResult {
+
+	inputFuncList := d.GetZoneInputFuncList(zone)
 	log.WithFields(log.Fields{
+		"zone":          zone,
 		"source":        source,
 		"inputFuncList": inputFuncList,
-		"sourceMapping": SOURCE_MAPPING}).Debug("Select Source Main Zone")
+		"sourceMapping": SOURCE_MAPPING}).Debug("Select Source")
 
 	var selectedSource string
 	for sourceOrigin, renamedSource := range inputFuncList {
@@ -139,9 +207,14 @@ func (d *DenonAVR) SetSelectSourceMainZone(source string) int {
 	}
 
 	if slices.Contains(TELNET_SOURCES, selectedSource) {
-		status, _ := d.sendCommandToDevice(DenonCommandSelectInput, selectedSource)
-		return status
+		// SICD for the main zone, Z2CD for Zone2
+		cmd := DenonCommandSelectInput
+		if zone != MainZone {
+			cmd = d.getZoneCommand(zone)
+		}
+		result, _ := d.sendCommandToDevice(cmd, selectedSource)
+		return result
 	}
 
-	return 404
+	return DenonCommandResultRejected
 }
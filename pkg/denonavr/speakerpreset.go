@@ -0,0 +1,45 @@
+package denonavr
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// Receivers store two speaker configurations
+const (
+	SPEAKER_PRESET_MIN int = 1
+	SPEAKER_PRESET_MAX int = 2
+)
+
+// Return the active speaker configuration, 1 or 2
+func (d *DenonAVR) GetSpeakerPreset() (int, error) {
+
+	preset, err := d.GetAttribute("SpeakerPreset")
+	if err != nil {
+		return 0, err
+	}
+
+	return preset.(int), nil
+}
+
+// Switch to the speaker configuration 1 or 2
+// Only available with the AppCommand0300 API
+func (d *DenonAVR) SetSpeakerPreset(preset int) error {
+
+	if !d.isAppCommand0300Available() {
+		return errors.New("speaker preset can only be set with the AppCommand0300 API")
+	}
+
+	if preset < SPEAKER_PRESET_MIN || preset > SPEAKER_PRESET_MAX {
+		return fmt.Errorf("invalid speaker preset %d", preset)
+	}
+
+	if err := d.SetAppCommand0300Parameters(DenonAppCommand0300SetSpeakerPreset, map[string]string{APPCOMMAND0300_PARAM_SPEAKER_PRESET: strconv.Itoa(preset)}); err != nil {
+		return err
+	}
+
+	d.SetAttribute("SpeakerPreset", preset)
+
+	return nil
+}
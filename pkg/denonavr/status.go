@@ -3,7 +3,6 @@ package denonavr
 import (
 	"encoding/xml"
 	"io"
-	"net/http"
 
 	log "github.com/sirupsen/logrus"
 )
@@ -33,29 +32,62 @@ func (d *DenonAVR) getZoneStatus(zone DenonZone) DenonZoneStatus {
 	var url string
 	switch zone {
 	case MainZone:
-		url = "http://" + d.Host + STATUS_URL
+		url = d.getURL(STATUS_URL)
 	case Zone2:
-		url = "http://" + d.Host + STATUS_Z2_URL
+		url = d.getURL(STATUS_Z2_URL)
 	case Zone3:
-		url = "http://" + d.Host + STATUS_Z3_URL
+		url = d.getURL(STATUS_Z3_URL)
 	}
 
-	zoneStatus, _ := d.getZoneStatusFromDevice(url)
+	zoneStatus, err := d.getZoneStatusFromDevice(url)
+	if err != nil {
+		// Keep the last known status
+		return d.getLastZoneStatus(zone)
+	}
+
+	d.statusMutex.Lock()
 	d.zoneStatus[zone] = *zoneStatus
+	d.statusMutex.Unlock()
 
-	return d.zoneStatus[zone]
+	return *zoneStatus
 
 }
 
+// Return the zone status of the last HTTP update
+func (d *DenonAVR) getLastZoneStatus(zone DenonZone) DenonZoneStatus {
+
+	d.statusMutex.Lock()
+	defer d.statusMutex.Unlock()
+
+	return d.zoneStatus[zone]
+}
+
 func (d *DenonAVR) getNetAudioStatus() {
-	url := "http://" + d.Host + NET_AUDIO_STATUR_URL
-	d.netAudioStatus = d.getNetAudioStatusFromDevice(url)
+	url := d.getURL(NET_AUDIO_STATUR_URL)
+	netAudioStatus, err := d.getNetAudioStatusFromDevice(url)
+	if err != nil {
+		// Keep the last known status
+		return
+	}
+
+	d.statusMutex.Lock()
+	d.netAudioStatus = *netAudioStatus
+	d.statusMutex.Unlock()
+}
+
+// Return the net audio status of the last HTTP update
+func (d *DenonAVR) getLastNetAudioStatus() DenonNetAudioStatus {
+
+	d.statusMutex.Lock()
+	defer d.statusMutex.Unlock()
+
+	return d.netAudioStatus
 }
 
 // Return the Status from a Zone
 func (d *DenonAVR) getZoneStatusFromDevice(url string) (*DenonZoneStatus, error) {
 	status := DenonZoneStatus{} // Somehow the values in the array are added instead of replaced. Not sure if this is the solution, but it works...
-	resp, err := http.Get(url)
+	resp, err := d.httpClient.Get(url)
 	if err != nil {
 		log.WithError(err).Error("Failed to get data from Denon AVR")
 		return nil, err
@@ -76,21 +108,24 @@ func (d *DenonAVR) getZoneStatusFromDevice(url string) (*DenonZoneStatus, error)
 }
 
 // Return the Status from a Zone
-func (d *DenonAVR) getNetAudioStatusFromDevice(url string) DenonNetAudioStatus {
+func (d *DenonAVR) getNetAudioStatusFromDevice(url string) (*DenonNetAudioStatus, error) {
 	status := DenonNetAudioStatus{} // Somehow the values in the array are added instead of replaced. Not sure if this is the solution, but it works...
-	resp, err := http.Get(url)
+	resp, err := d.httpClient.Get(url)
 	if err != nil {
-		log.Fatalln(err)
+		log.WithError(err).Error("Failed to get data from Denon AVR")
+		return nil, err
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		log.WithError(err).Error("Cannot read response body")
+		return nil, err
 	}
 
 	if err := xml.Unmarshal(body, &status); err != nil {
 		log.WithError(err).Info("Could not unmarshall")
+		return nil, err
 	}
 
-	return status
+	return &status, nil
 }
@@ -0,0 +1,49 @@
+package denonavr
+
+import (
+	"strings"
+)
+
+// Handle the surround parameter telnet events
+// PSBAS 50, PSTONE CTRL ON, ...
+func (d *DenonAVR) handleSurroundParameterTelnetEvent(param string) {
+
+	switch {
+	case strings.HasPrefix(param, "TONE CTRL "):
+		d.SetAttribute("ToneControl", strings.TrimPrefix(param, "TONE CTRL "))
+	case strings.HasPrefix(param, "BAS "):
+		d.handleToneTelnetEvent("Bass", strings.TrimPrefix(param, "BAS "))
+	case strings.HasPrefix(param, "TRE "):
+		d.handleToneTelnetEvent("Treble", strings.TrimPrefix(param, "TRE "))
+	case strings.HasPrefix(param, "MULTEQ:"):
+		d.SetAttribute("MultEQ", strings.TrimPrefix(param, "MULTEQ:"))
+	case strings.HasPrefix(param, "DYNEQ "):
+		d.SetAttribute("DynamicEQ", strings.TrimPrefix(param, "DYNEQ "))
+	case strings.HasPrefix(param, "REFLEV "):
+		d.SetAttribute("ReferenceLevel", strings.TrimPrefix(param, "REFLEV "))
+	case strings.HasPrefix(param, "DYNVOL "):
+		d.SetAttribute("DynamicVolume", strings.TrimPrefix(param, "DYNVOL "))
+	case strings.HasPrefix(param, "DIL "):
+		d.handleAdjustableLevelTelnetEvent("DialogLevel", strings.TrimPrefix(param, "DIL "), DIALOG_LEVEL_OFFSET)
+	case strings.HasPrefix(param, "DIC "):
+		d.SetAttribute("DialogEnhancer", strings.TrimPrefix(param, "DIC "))
+	case strings.HasPrefix(param, "SWL "):
+		d.handleAdjustableLevelTelnetEvent("SubwooferLevel", strings.TrimPrefix(param, "SWL "), SUBWOOFER_LEVEL_OFFSET)
+	case strings.HasPrefix(param, "DRC "):
+		d.handleDecoderSettingTelnetEvent("DynamicRangeCompression", strings.TrimPrefix(param, "DRC "))
+	case strings.HasPrefix(param, "LFE "):
+		d.handleDecoderSettingTelnetEvent("LFELevel", strings.TrimPrefix(param, "LFE "))
+	case strings.HasPrefix(param, "CINEMA EQ."):
+		d.handleDecoderSettingTelnetEvent("CinemaEQ", strings.TrimPrefix(param, "CINEMA EQ."))
+	case strings.HasPrefix(param, "LOM "):
+		d.handleDecoderSettingTelnetEvent("LoudnessManagement", strings.TrimPrefix(param, "LOM "))
+	case strings.HasPrefix(param, "SPV "):
+		d.handleUpmixerTelnetEvent(DenonUpmixerOptionSpeakerVirtualizer, strings.TrimPrefix(param, "SPV "))
+	case strings.HasPrefix(param, "NEURAL "):
+		d.handleUpmixerTelnetEvent(DenonUpmixerOptionNeuralX, strings.TrimPrefix(param, "NEURAL "))
+	case strings.HasPrefix(param, "CES "):
+		d.handleUpmixerTelnetEvent(DenonUpmixerOptionCenterSpread, strings.TrimPrefix(param, "CES "))
+	case strings.HasPrefix(param, "HEG "):
+		d.handleUpmixerTelnetEvent(DenonUpmixerOptionHeightVirtualization, strings.TrimPrefix(param, "HEG "))
+	}
+}
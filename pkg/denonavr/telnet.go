@@ -7,10 +7,101 @@ import (
 	"strings"
 	"time"
 
+	"github.com/cenkalti/backoff"
 	log "github.com/sirupsen/logrus"
 	"github.com/ziutek/telnet"
 )
 
+type telnetStatusQuery struct {
+	Command DenonCommand
+	Payload string
+}
+
+// Queries sent first after each (re)connect to get the core state of the device
+// The replies are handled as telnet events
+var TELNET_STATUS_QUERIES = []telnetStatusQuery{
+	{DenonCommandPower, "?"},
+	{DennonCommandZoneMain, "?"},
+	{DenonCommandMainZoneVolume, "?"},
+	{DenonCommandMainZoneMute, "?"},
+	{DenonCommandSelectInput, "?"},
+	{DenonCommandMS, "?"},
+	{DenonCommandZone2, "?"},
+	{DenonCommandZone3, "?"},
+}
+
+// Queries sent with low priority after the TELNET_STATUS_QUERIES to get the remaining state
+// User commands are sent in between
+var TELNET_EXTENDED_STATUS_QUERIES = []telnetStatusQuery{
+	{DenonCommandMS, "QUICK ?"},
+	{DenonCommandMS, "SMART ?"},
+	{DenonCommandZone2, "MU?"},
+	{DenonCommandZone2, "CS?"},
+	{DenonCommandZone3, "MU?"},
+	{DenonCommandZone3, "CS?"},
+	{DenonCommandSleep, "?"},
+	{DenonCommandZone2, "SLP?"},
+	{DenonCommandZone3, "SLP?"},
+	{DenonCommandECO, "?"},
+	{DenonCommandDimmer, " ?"},
+	{DenonCommandTunerMode, "AN?"},
+	{DenonCommandTunerFrequency, "AN?"},
+	{DenonCommandTunerFrequency, "ANNAME?"},
+	{DenonCommandTunerPreset, "AN?"},
+	{DenonCommandTunerPresetName, " ?"},
+	{DenonCommandChannelVolume, "?"},
+	{DenonCommandPS, "TONE CTRL ?"},
+	{DenonCommandPS, "BAS ?"},
+	{DenonCommandPS, "TRE ?"},
+	{DenonCommandPS, "MULTEQ: ?"},
+	{DenonCommandPS, "DYNEQ ?"},
+	{DenonCommandPS, "REFLEV ?"},
+	{DenonCommandPS, "DYNVOL ?"},
+	{DenonCommandPS, "DIL ?"},
+	{DenonCommandPS, "DIC ?"},
+	{DenonCommandPS, "SWL ?"},
+	{DenonCommandPS, "DRC ?"},
+	{DenonCommandPS, "LFE ?"},
+	{DenonCommandPS, "CINEMA EQ. ?"},
+	{DenonCommandPS, "LOM ?"},
+	{DenonCommandPS, "SPV ?"},
+	{DenonCommandPS, "NEURAL ?"},
+	{DenonCommandPS, "CES ?"},
+	{DenonCommandPS, "HEG ?"},
+}
+
+// State of the telnet connection, available as TelnetConnectionState attribute
+type TelnetConnectionState string
+
+const (
+	TelnetConnectionStateConnecting   TelnetConnectionState = "CONNECTING"
+	TelnetConnectionStateConnected    TelnetConnectionState = "CONNECTED"
+	TelnetConnectionStateReconnecting TelnetConnectionState = "RECONNECTING"
+	TelnetConnectionStateDisconnected TelnetConnectionState = "DISCONNECTED"
+)
+
+// Reconnect intervals when the telnet connection is lost, e.g. when the device reboots
+const (
+	TELNET_RECONNECT_INITIAL_INTERVAL = 1 * time.Second
+	TELNET_RECONNECT_MAX_INTERVAL     = 60 * time.Second
+	TELNET_RECONNECT_JITTER           = 0.5
+	// The backoff starts again with the initial interval only if the connection was up this long
+	// The device accepts and immediately closes a connection if its telnet connection is already in use
+	TELNET_RECONNECT_MIN_UPTIME = 30 * time.Second
+)
+
+// Time to wait for the echo of a telnet command
+const TELNET_ACKNOWLEDGEMENT_TIMEOUT = 1 * time.Second
+
+// The receivers serve telnet on this port only, independent of the port of the control API
+const TELNET_PORT string = "23"
+
+// A telnet command waiting for its echo, e.g. MVUP -> MV52
+type telnetAcknowledgement struct {
+	isEcho func(rawData string) bool
+	result chan DenonCommandResult
+}
+
 type TelnetEvent struct {
 	RawData string
 	Command string
@@ -23,6 +114,13 @@ func (d *DenonAVR) handleTelnetEvents(controlChannel chan string) {
 	for {
 		select {
 		case event := <-d.telnetEvents:
+			// Acknowledge the commands waiting for this event
+			d.acknowledgeTelnetCommands(event.RawData)
+
+			if len(event.Command) < 2 {
+				// not a valid event
+				continue
+			}
 			parsedCommand := strings.Split(event.Command, "")
 			command := parsedCommand[0] + parsedCommand[1]
 			param := strings.Join(parsedCommand[2:], "")
@@ -32,6 +130,26 @@ func (d *DenonAVR) handleTelnetEvents(controlChannel chan string) {
 				continue
 			}
 
+			// Commands with more than two characters
+			switch {
+			case isTelnetSleepEvent(event.RawData):
+				// SLPOFF, SLP060
+				d.handleSleepTelnetEvent(MainZone, strings.TrimPrefix(event.RawData, string(DenonCommandSleep)))
+				continue
+			case isTelnetECOEvent(event.RawData):
+				// ECOON, ECOAUTO, ECOOFF
+				d.handleECOTelnetEvent(strings.TrimPrefix(event.RawData, string(DenonCommandECO)))
+				continue
+			case isTelnetDimmerEvent(event.RawData):
+				// DIM BRI, DIM DAR
+				d.handleDimmerTelnetEvent(strings.TrimPrefix(event.RawData, string(DenonCommandDimmer)))
+				continue
+			case isTelnetTunerPresetNameEvent(event.RawData):
+				// OPTPN01BBC R1
+				d.handleTunerPresetNameTelnetEvent(strings.TrimPrefix(event.RawData, string(DenonCommandTunerPresetName)))
+				continue
+			}
+
 			log.WithFields(log.Fields{
 				"cmd":     event.Command,
 				"payload": event.Payload,
@@ -44,27 +162,54 @@ func (d *DenonAVR) handleTelnetEvents(controlChannel chan string) {
 				d.SetAttribute("POWER", param)
 			case DennonCommandZoneMain:
 				d.SetAttribute("MainZonePower", param)
+				if d.telnetOnly {
+					go d.updateMediaAndNotify()
+				}
 			case DenonCommandMainZoneVolume:
 				if param != "MAX" {
-
-					volume, err := strconv.ParseFloat(param, 32)
+					volume, err := parseTelnetVolume(param)
 					if err != nil {
 						log.WithError(err).Error("failed to parse volume")
+						continue
 					}
-
-					// The Volume command need the following
-					// 10.5 -> MV105
-					// 11 -> MV11
-					if len(param) == 3 {
-						volume = volume / 10
-						log.WithField("volume", volume).Debug("Got volume after conversion")
-					}
-
-					d.SetAttribute("MainZoneVolume", fmt.Sprintf("%0.1f", volume-80))
+					d.SetAttribute("MainZoneVolume", volume)
 				}
 
 			case DenonCommandMainZoneMute:
 				d.SetAttribute("MainZoneMute", strings.ToLower(param))
+			case DenonCommandSelectInput:
+				source := strings.TrimPrefix(event.RawData, command)
+				if isTelnetSource(source) {
+					d.SetAttribute("MainZoneInputFuncSelect", d.getRenamedTelnetSource(MainZone, source))
+					if d.telnetOnly {
+						// Not polled via HTTP, but needed for the media title and image
+						d.setMainZoneInputFuncSelect(source)
+						go d.updateMediaAndNotify()
+					}
+				}
+			case DenonCommandMS:
+				surroundMode := strings.TrimPrefix(event.RawData, command)
+				// MSQUICK1 and MSSMART1 are the quick select, not a surround mode
+				if isTelnetQuickSelect(surroundMode) {
+					d.handleQuickSelectTelnetEvent(surroundMode)
+					continue
+				}
+				d.SetAttribute("MainZoneSurroundMode", d.getSurroundModeCategory(surroundMode))
+			case DenonCommandChannelVolume:
+				// CVFL 50, CVC 535, CVEND
+				d.handleChannelVolumeTelnetEvent(DenonChannel(param), event.Payload)
+			case DenonCommandPS:
+				d.handleSurroundParameterTelnetEvent(strings.TrimPrefix(event.RawData, command))
+			case DenonCommandTunerFrequency:
+				d.handleTunerFrequencyTelnetEvent(strings.TrimPrefix(event.RawData, command))
+			case DenonCommandTunerPreset:
+				d.handleTunerPresetTelnetEvent(strings.TrimPrefix(event.RawData, command))
+			case DenonCommandTunerMode:
+				d.handleTunerModeTelnetEvent(strings.TrimPrefix(event.RawData, command))
+			case DenonCommandZone2:
+				d.handleZoneTelnetEvent(Zone2, strings.TrimPrefix(event.RawData, command))
+			case DenonCommandZone3:
+				d.handleZoneTelnetEvent(Zone3, strings.TrimPrefix(event.RawData, command))
 			}
 		case msg := <-controlChannel:
 			if msg == "disconnect" {
@@ -74,9 +219,93 @@ func (d *DenonAVR) handleTelnetEvents(controlChannel chan string) {
 	}
 }
 
+// Handle the telnet events for Zone2 and Zone3
+// Z2ON, Z2OFF, Z250, Z2MUON, Z2CSST, Z2CD, ...
+func (d *DenonAVR) handleZoneTelnetEvent(zone DenonZone, param string) {
+
+	zoneName := d.GetZoneName(zone)
+
+	switch {
+	case param == "ON" || param == "OFF":
+		d.SetAttribute(zoneName+"Power", param)
+	case strings.HasPrefix(param, "MU"):
+		d.SetAttribute(zoneName+"Mute", strings.ToLower(strings.TrimPrefix(param, "MU")))
+	case strings.HasPrefix(param, "CS"):
+		for mode, channelSetting := range ZONE_SOUND_MODE_MAPPING {
+			if channelSetting == strings.TrimPrefix(param, "CS") {
+				d.SetAttribute(zoneName+"SurroundMode", mode)
+			}
+		}
+	case isTelnetSleepEvent(param):
+		d.handleSleepTelnetEvent(zone, strings.TrimPrefix(param, string(DenonCommandSleep)))
+	case isTelnetVolume(param):
+		volume, err := parseTelnetVolume(param)
+		if err != nil {
+			log.WithError(err).Error("failed to parse volume")
+			return
+		}
+		d.SetAttribute(zoneName+"Volume", volume)
+	case isTelnetSource(param):
+		d.SetAttribute(zoneName+"InputFuncSelect", d.getRenamedTelnetSource(zone, param))
+	}
+}
+
+// Check if the param of a telnet event is a volume value
+func isTelnetVolume(param string) bool {
+	if len(param) < 2 || len(param) > 3 {
+		return false
+	}
+
+	_, err := strconv.Atoi(param)
+	return err == nil
+}
+
+// Convert the volume of a telnet event to the value used in the attributes
+// The Volume is sent as the following
+// MV105 -> 10.5
+// MV11 -> 11
+func parseTelnetVolume(param string) (string, error) {
+
+	volume, err := parseTelnetLevel(param, 80)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%0.1f", volume), nil
+}
+
+// Convert a level of a telnet event to dB
+// Levels are sent with two digits or with three digits for half steps, relative to the offset
+// CVC 535 -> 3.5 with offset 50
+func parseTelnetLevel(param string, offset float64) (float64, error) {
+
+	level, err := strconv.ParseFloat(param, 32)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(param) == 3 {
+		level = level / 10
+		log.WithField("level", level).Debug("Got level after conversion")
+	}
+
+	return level - offset, nil
+}
+
+// Return the telnet address of the receiver, a port of the host is replaced by the telnet port
+func (d *DenonAVR) getTelnetAddress() string {
+
+	host := d.Host
+	if h, _, err := net.SplitHostPort(d.Host); err == nil {
+		host = h
+	}
+
+	return net.JoinHostPort(host, TELNET_PORT)
+}
+
 func (d *DenonAVR) ConnectTelnet() (*telnet.Conn, error) {
 
-	telnet, err := telnet.DialTimeout("tcp", d.Host+":23", 5*time.Second)
+	telnet, err := telnet.DialTimeout("tcp", d.getTelnetAddress(), 5*time.Second)
 	if err != nil {
 		log.WithError(err).Error("failed to connect to telnet")
 		return nil, err
@@ -92,12 +321,64 @@ func (d *DenonAVR) ConnectTelnet() (*telnet.Conn, error) {
 		return nil, err
 	}
 
-	log.WithField("host", d.Host+":23").Debug("Telnet connected")
+	log.WithField("host", d.getTelnetAddress()).Debug("Telnet connected")
 
 	return telnet, nil
 }
 
-func (d *DenonAVR) listenTelnet(controlChannel chan string) (error, bool) {
+// Exponential backoff with jitter for the telnet reconnects
+func newTelnetReconnectBackOff() *backoff.ExponentialBackOff {
+
+	reconnectBackOff := backoff.NewExponentialBackOff()
+	reconnectBackOff.InitialInterval = TELNET_RECONNECT_INITIAL_INTERVAL
+	reconnectBackOff.MaxInterval = TELNET_RECONNECT_MAX_INTERVAL
+	reconnectBackOff.RandomizationFactor = TELNET_RECONNECT_JITTER
+	// Never stop reconnecting
+	reconnectBackOff.MaxElapsedTime = 0
+	// NewExponentialBackOff already started with the default initial interval
+	reconnectBackOff.Reset()
+
+	return reconnectBackOff
+}
+
+// Keep the telnet connection up until the controlChannel is closed
+// Reconnect with an exponential backoff and jitter when the connection is lost or cannot be established
+func (d *DenonAVR) superviseTelnet(controlChannel chan string) {
+
+	reconnectBackOff := newTelnetReconnectBackOff()
+
+	d.SetAttribute("TelnetConnectionState", TelnetConnectionStateConnecting)
+
+	for {
+		log.Debug("Starting Telnet Loop")
+		err, uptime := d.listenTelnet(controlChannel)
+		if err == nil {
+			// Disconnect requested
+			d.SetAttribute("TelnetConnectionState", TelnetConnectionStateDisconnected)
+			return
+		}
+
+		// Start with the initial interval again if the connection was stable
+		if uptime >= TELNET_RECONNECT_MIN_UPTIME {
+			reconnectBackOff.Reset()
+		}
+
+		interval := reconnectBackOff.NextBackOff()
+		log.WithError(err).WithField("interval", interval).Info("Telnet connection lost, reconnecting")
+		d.SetAttribute("TelnetConnectionState", TelnetConnectionStateReconnecting)
+
+		select {
+		case <-time.After(interval):
+		case <-controlChannel:
+			d.SetAttribute("TelnetConnectionState", TelnetConnectionStateDisconnected)
+			return
+		}
+	}
+}
+
+// Connect and listen to telnet events until the connection is lost or the controlChannel is closed
+// Returns a nil error when closed via the controlChannel and how long the connection was established
+func (d *DenonAVR) listenTelnet(controlChannel chan string) (error, time.Duration) {
 
 	log.Debug("Start Telnet listen loop")
 
@@ -107,23 +388,35 @@ func (d *DenonAVR) listenTelnet(controlChannel chan string) (error, bool) {
 		log.Debug("Closing Telnet connection")
 		// Make sure eventHandler loop is also closed
 		eventHandlerControlChannel <- "disconnect"
+		d.telnetMutex.Lock()
 		if d.telnet != nil {
 			if err := d.telnet.Close(); err != nil {
 				log.WithError(err).Debug("Telnet connection (already) closed")
 			}
+			d.telnet = nil
 		}
+		d.telnetMutex.Unlock()
+		// No echo will arrive for the commands still waiting
+		d.cancelTelnetAcknowledgements()
 	}()
 
 	go d.handleTelnetEvents(eventHandlerControlChannel)
 
-	var err error
-
-	d.telnet, err = d.ConnectTelnet()
+	telnet, err := d.ConnectTelnet()
 	if err != nil {
-		return err, false
+		return err, 0
 	}
+	connectedAt := time.Now()
+
+	d.telnetMutex.Lock()
+	d.telnet = telnet
+	d.telnetMutex.Unlock()
 
-	log.WithField("host", d.Host+":23").Debug("Telnet connected")
+	log.WithField("host", d.getTelnetAddress()).Debug("Telnet connected")
+	d.SetAttribute("TelnetConnectionState", TelnetConnectionStateConnected)
+
+	// Get the full state of the device, the replies are handled in the event handler
+	go d.queryTelnetStatus()
 
 	dataChannel := make(chan string)
 
@@ -134,8 +427,8 @@ func (d *DenonAVR) listenTelnet(controlChannel chan string) (error, bool) {
 		case data := <-dataChannel:
 			if data == "" {
 				log.Debug("No Data from Telnet received")
-				// Return the error but try to reconnect as we just lost the connection
-				return fmt.Errorf("failed to read form telnet"), true
+				// Return the error, the connection is reestablished by the supervisor
+				return fmt.Errorf("failed to read form telnet"), time.Since(connectedAt)
 			}
 			parsedData := strings.Split(data, " ")
 			event := TelnetEvent{}
@@ -147,18 +440,54 @@ func (d *DenonAVR) listenTelnet(controlChannel chan string) (error, bool) {
 
 			// Fire Event for handling
 			d.telnetEvents <- &event
-		case msg := <-controlChannel:
-			if msg == "disconnect" {
-				return nil, false
-			}
+		case <-controlChannel:
+			return nil, time.Since(connectedAt)
 		}
 
 	}
 }
 
+// Send all status queries to the device
+// The queries are sent through the command queue, so they don't get dropped
+// The core state is queried first, the remaining state with low priority
+func (d *DenonAVR) queryTelnetStatus() {
+
+	log.Debug("Query status via telnet")
+
+	for _, query := range TELNET_STATUS_QUERIES {
+		if _, err := d.commandQueue.sendCommand(query.Command, query.Payload, d.sendTelnetQuery); err != nil {
+			return
+		}
+	}
+
+	for _, query := range TELNET_EXTENDED_STATUS_QUERIES {
+		if _, err := d.commandQueue.sendLowPriorityCommand(query.Command, query.Payload, d.sendTelnetQuery); err != nil {
+			return
+		}
+	}
+}
+
+func (d *DenonAVR) sendTelnetQuery(cmd DenonCommand, payload string) (DenonCommandResult, error) {
+
+	if err := d.sendTelnetCommand(cmd, payload); err != nil {
+		return DenonCommandResultDisconnected, err
+	}
+
+	return DenonCommandResultAccepted, nil
+}
+
 func (d *DenonAVR) telnetReadString(dataChannel chan string) {
 
-	data, err := d.telnet.ReadString('\r')
+	d.telnetMutex.Lock()
+	telnet := d.telnet
+	d.telnetMutex.Unlock()
+
+	if telnet == nil {
+		close(dataChannel)
+		return
+	}
+
+	data, err := telnet.ReadString('\r')
 	data = strings.Trim(data, " \n\r")
 	if err != nil {
 		log.WithError(err).Errorf("failed to read form telnet")
@@ -180,9 +509,189 @@ func (d *DenonAVR) sendTelnetCommand(cmd DenonCommand, payload string) error {
 
 	if d.telnet != nil {
 		_, err := d.telnet.Write([]byte(string(cmd) + payload + "\r"))
-		log.WithError(err).Error("Failed to send telnet command")
+		if err != nil {
+			log.WithError(err).Error("Failed to send telnet command")
+		}
 		return err
 	}
 
 	return fmt.Errorf("cannot send telnet command, no telnet connection available")
 }
+
+// Send a telnet command and wait for its echo from the device
+func (d *DenonAVR) sendTelnetCommandAndWait(cmd DenonCommand, payload string) (DenonCommandResult, error) {
+
+	isEcho, hasEcho := getTelnetEchoMatcher(cmd, payload)
+
+	// Register before sending, the echo can arrive before the write returns
+	var acknowledgement *telnetAcknowledgement
+	if hasEcho {
+		acknowledgement = d.addTelnetAcknowledgement(isEcho)
+	}
+
+	if err := d.sendTelnetCommand(cmd, payload); err != nil {
+		if acknowledgement != nil {
+			d.removeTelnetAcknowledgement(acknowledgement)
+		}
+		return DenonCommandResultDisconnected, err
+	}
+
+	if acknowledgement == nil {
+		return DenonCommandResultAccepted, nil
+	}
+
+	select {
+	case result := <-acknowledgement.result:
+		if result != DenonCommandResultAccepted {
+			return result, fmt.Errorf("telnet command %s%s not acknowledged: %s", cmd, payload, result)
+		}
+		return result, nil
+	case <-time.After(TELNET_ACKNOWLEDGEMENT_TIMEOUT):
+		d.removeTelnetAcknowledgement(acknowledgement)
+		log.WithFields(log.Fields{
+			"cmd":     string(cmd),
+			"payload": payload,
+		}).Debug("No echo received for telnet command")
+		return DenonCommandResultTimedOut, fmt.Errorf("timeout waiting for the echo of telnet command %s%s", cmd, payload)
+	}
+}
+
+// Return a function matching the event the device sends as echo of a command
+// Some commands have no echo, e.g. the cursor control or storing a quick select, they are accepted without waiting
+func getTelnetEchoMatcher(cmd DenonCommand, payload string) (func(rawData string) bool, bool) {
+
+	switch cmd {
+	case DenonCommandCursorControl, DenonCommandNS:
+		return nil, false
+	case DenonCommandPS, DenonCommandChannelVolume:
+		// The echo contains the parameter, PSBAS UP -> PSBAS 51, CVC UP -> CVC 51, PSMULTEQ:FLAT -> PSMULTEQ:FLAT
+		if i := strings.IndexAny(payload, " :"); i > 0 {
+			return hasTelnetEchoPrefix(string(cmd) + payload[:i+1]), true
+		}
+	case DenonCommandMainZoneVolume:
+		// MVUP -> MV52, MV455 -> MV455
+		return isTelnetVolumeEcho(string(cmd), payload), true
+	case DenonCommandZone2, DenonCommandZone3:
+		switch {
+		case payload == "UP" || payload == "DOWN" || isTelnetVolume(payload):
+			return isTelnetVolumeEcho(string(cmd), payload), true
+		case isTelnetSleepEvent(payload):
+			// Z2SLP030 -> Z2SLP030, Z2SLPOFF -> Z2SLPOFF
+			return hasTelnetEchoPrefix(string(cmd) + string(DenonCommandSleep)), true
+		}
+		// Z2ON, Z2MUON, Z2CSST, Z2CD
+		return isTelnetEvent(string(cmd) + payload), true
+	case DenonCommandPower, DennonCommandZoneMain, DenonCommandMainZoneMute, DenonCommandSelectInput:
+		// PWON, ZMOFF, MUON, SICD
+		return isTelnetEvent(string(cmd) + payload), true
+	case DenonCommandMS:
+		if isTelnetQuickSelect(payload) {
+			// MSQUICK1 -> MSQUICK1, storing with MSQUICK1 MEMORY has no echo
+			if strings.HasSuffix(payload, "MEMORY") {
+				return nil, false
+			}
+			return isTelnetEvent(string(cmd) + payload), true
+		}
+		// The echo is the surround mode, which differs from the sound mode sent, e.g. MSMOVIE -> MSDOLBY DIGITAL
+		return func(rawData string) bool {
+			surroundMode, found := strings.CutPrefix(rawData, string(cmd))
+			return found && !isTelnetQuickSelect(surroundMode)
+		}, true
+	case DenonCommandTunerFrequency, DenonCommandTunerPreset, DenonCommandTunerMode:
+		// TFANUP -> TFAN010570, TPAN01 -> TPAN01
+		return hasTelnetEchoPrefix(string(cmd) + "AN"), true
+	}
+
+	return hasTelnetEchoPrefix(string(cmd)), true
+}
+
+func hasTelnetEchoPrefix(prefix string) func(rawData string) bool {
+	return func(rawData string) bool {
+		return strings.HasPrefix(rawData, prefix)
+	}
+}
+
+func isTelnetEvent(event string) func(rawData string) bool {
+	return func(rawData string) bool {
+		return strings.EqualFold(rawData, event)
+	}
+}
+
+// Match the volume reported after a volume command
+// Relative commands match any volume, absolute commands only the volume sent
+func isTelnetVolumeEcho(cmd string, payload string) func(rawData string) bool {
+	return func(rawData string) bool {
+		volume, found := strings.CutPrefix(rawData, cmd)
+		if !found || !isTelnetVolume(volume) {
+			return false
+		}
+
+		if payload == "UP" || payload == "DOWN" {
+			return true
+		}
+
+		echoLevel, err := parseTelnetLevel(volume, 0)
+		if err != nil {
+			return false
+		}
+		level, err := parseTelnetLevel(payload, 0)
+
+		return err == nil && level == echoLevel
+	}
+}
+
+func (d *DenonAVR) addTelnetAcknowledgement(isEcho func(rawData string) bool) *telnetAcknowledgement {
+
+	d.acknowledgementMutex.Lock()
+	defer d.acknowledgementMutex.Unlock()
+
+	acknowledgement := &telnetAcknowledgement{
+		isEcho: isEcho,
+		result: make(chan DenonCommandResult, 1),
+	}
+	d.telnetAcknowledgements = append(d.telnetAcknowledgements, acknowledgement)
+
+	return acknowledgement
+}
+
+func (d *DenonAVR) removeTelnetAcknowledgement(acknowledgement *telnetAcknowledgement) {
+
+	d.acknowledgementMutex.Lock()
+	defer d.acknowledgementMutex.Unlock()
+
+	for i, a := range d.telnetAcknowledgements {
+		if a == acknowledgement {
+			d.telnetAcknowledgements = append(d.telnetAcknowledgements[:i], d.telnetAcknowledgements[i+1:]...)
+			return
+		}
+	}
+}
+
+// Acknowledge all commands waiting for an echo matching this telnet event
+func (d *DenonAVR) acknowledgeTelnetCommands(rawData string) {
+
+	d.acknowledgementMutex.Lock()
+	defer d.acknowledgementMutex.Unlock()
+
+	waiting := d.telnetAcknowledgements[:0]
+	for _, acknowledgement := range d.telnetAcknowledgements {
+		if acknowledgement.isEcho(rawData) {
+			acknowledgement.result <- DenonCommandResultAccepted
+			continue
+		}
+		waiting = append(waiting, acknowledgement)
+	}
+	d.telnetAcknowledgements = waiting
+}
+
+// Stop waiting for the echo of all commands, e.g. when the telnet connection is lost
+func (d *DenonAVR) cancelTelnetAcknowledgements() {
+
+	d.acknowledgementMutex.Lock()
+	defer d.acknowledgementMutex.Unlock()
+
+	for _, acknowledgement := range d.telnetAcknowledgements {
+		acknowledgement.result <- DenonCommandResultDisconnected
+	}
+	d.telnetAcknowledgements = nil
+}
@@ -0,0 +1,276 @@
+package denonavr
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ziutek/telnet"
+)
+
+func TestTelnetEchoMatcher(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		cmd      DenonCommand
+		payload  string
+		rawData  string
+		wantEcho bool
+	}{
+		{"volume up", DenonCommandMainZoneVolume, "UP", "MV52", true},
+		{"volume down half step", DenonCommandMainZoneVolume, "DOWN", "MV515", true},
+		{"volume up max volume", DenonCommandMainZoneVolume, "UP", "MVMAX 80", false},
+		{"absolute volume", DenonCommandMainZoneVolume, "455", "MV455", true},
+		{"absolute volume other level", DenonCommandMainZoneVolume, "455", "MV45", false},
+		{"absolute volume other event", DenonCommandMainZoneVolume, "50", "MU50", false},
+		{"power on", DenonCommandPower, "ON", "PWON", true},
+		{"power on standby", DenonCommandPower, "ON", "PWSTANDBY", false},
+		{"main zone off", DennonCommandZoneMain, "OFF", "ZMOFF", true},
+		{"main zone on other state", DennonCommandZoneMain, "ON", "ZMOFF", false},
+		{"mute", DenonCommandMainZoneMute, "ON", "MUON", true},
+		{"zone2 on", DenonCommandZone2, "ON", "Z2ON", true},
+		{"zone2 on zone3 event", DenonCommandZone2, "ON", "Z3ON", false},
+		{"zone2 volume up", DenonCommandZone2, "UP", "Z245", true},
+		{"zone2 absolute volume", DenonCommandZone2, "50", "Z250", true},
+		{"zone2 absolute volume other level", DenonCommandZone2, "50", "Z251", false},
+		{"zone2 mute", DenonCommandZone2, "MUON", "Z2MUON", true},
+		{"zone2 source", DenonCommandZone2, "CD", "Z2CD", true},
+		{"zone2 source volume event", DenonCommandZone2, "CD", "Z250", false},
+		{"zone2 sleep", DenonCommandZone2, "SLP030", "Z2SLP030", true},
+		{"zone2 sleep off", DenonCommandZone2, "SLPOFF", "Z2SLP030", true},
+		{"ps level", DenonCommandPS, "BAS UP", "PSBAS 51", true},
+		{"ps level other parameter", DenonCommandPS, "BAS UP", "PSTRE 50", false},
+		{"ps setting", DenonCommandPS, "MULTEQ:FLAT", "PSMULTEQ:FLAT", true},
+		{"ps setting other parameter", DenonCommandPS, "MULTEQ:FLAT", "PSDYNEQ ON", false},
+		{"channel volume", DenonCommandChannelVolume, "C UP", "CVC 51", true},
+		{"sound mode", DenonCommandMS, "MOVIE", "MSDOLBY DIGITAL", true},
+		{"sound mode quick select event", DenonCommandMS, "MOVIE", "MSQUICK1", false},
+		{"quick select", DenonCommandMS, "QUICK1", "MSQUICK1", true},
+		{"tuner frequency", DenonCommandTunerFrequency, "ANUP", "TFAN010570", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			isEcho, hasEcho := getTelnetEchoMatcher(tt.cmd, tt.payload)
+			if !hasEcho {
+				t.Fatalf("getTelnetEchoMatcher(%s, %s) has no echo", tt.cmd, tt.payload)
+			}
+			if got := isEcho(tt.rawData); got != tt.wantEcho {
+				t.Errorf("echo of %s%s matches %s = %v, want %v", tt.cmd, tt.payload, tt.rawData, got, tt.wantEcho)
+			}
+		})
+	}
+}
+
+func TestTelnetEchoMatcherWithoutEcho(t *testing.T) {
+
+	tests := []struct {
+		cmd     DenonCommand
+		payload string
+	}{
+		{DenonCommandCursorControl, "CUP"},
+		{DenonCommandNS, "94"},
+		{DenonCommandMS, "QUICK1 MEMORY"},
+	}
+
+	for _, tt := range tests {
+		if _, hasEcho := getTelnetEchoMatcher(tt.cmd, tt.payload); hasEcho {
+			t.Errorf("getTelnetEchoMatcher(%s, %s) has an echo, want none", tt.cmd, tt.payload)
+		}
+	}
+}
+
+// Connect the receiver to a fake device, the commands the device receives are sent to the returned channel
+func newTestTelnetConnection(t *testing.T, d *DenonAVR) chan string {
+
+	client, server := net.Pipe()
+	t.Cleanup(func() {
+		client.Close()
+		server.Close()
+	})
+
+	conn, err := telnet.NewConn(client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.telnet = conn
+
+	received := make(chan string, 10)
+	go func() {
+		reader := bufio.NewReader(server)
+		for {
+			data, err := reader.ReadString('\r')
+			if err != nil {
+				return
+			}
+			received <- strings.TrimSuffix(data, "\r")
+		}
+	}()
+
+	return received
+}
+
+func TestSendTelnetCommandAndWait(t *testing.T) {
+
+	tests := []struct {
+		name           string
+		cmd            DenonCommand
+		payload        string
+		connected      bool
+		device         func(d *DenonAVR)
+		wantResult     DenonCommandResult
+		wantStatusCode int
+	}{
+		{
+			name:      "echo",
+			cmd:       DenonCommandMainZoneVolume,
+			payload:   "UP",
+			connected: true,
+			device: func(d *DenonAVR) {
+				d.acknowledgeTelnetCommands("MV52")
+			},
+			wantResult:     DenonCommandResultAccepted,
+			wantStatusCode: 200,
+		},
+		{
+			name:      "other event",
+			cmd:       DenonCommandPower,
+			payload:   "ON",
+			connected: true,
+			device: func(d *DenonAVR) {
+				d.acknowledgeTelnetCommands("PWSTANDBY")
+			},
+			wantResult:     DenonCommandResultTimedOut,
+			wantStatusCode: 408,
+		},
+		{
+			name:           "timeout",
+			cmd:            DenonCommandPower,
+			payload:        "ON",
+			connected:      true,
+			device:         func(d *DenonAVR) {},
+			wantResult:     DenonCommandResultTimedOut,
+			wantStatusCode: 408,
+		},
+		{
+			name:      "disconnect",
+			cmd:       DennonCommandZoneMain,
+			payload:   "OFF",
+			connected: true,
+			device: func(d *DenonAVR) {
+				d.cancelTelnetAcknowledgements()
+			},
+			wantResult:     DenonCommandResultDisconnected,
+			wantStatusCode: 503,
+		},
+		{
+			name:           "no connection",
+			cmd:            DenonCommandPower,
+			payload:        "ON",
+			connected:      false,
+			wantResult:     DenonCommandResultDisconnected,
+			wantStatusCode: 503,
+		},
+		{
+			name:           "no echo expected",
+			cmd:            DenonCommandCursorControl,
+			payload:        "CUP",
+			connected:      true,
+			device:         func(d *DenonAVR) {},
+			wantResult:     DenonCommandResultAccepted,
+			wantStatusCode: 200,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			d := NewDenonAVR("127.0.0.1", true, false)
+
+			if tt.connected {
+				received := newTestTelnetConnection(t, d)
+
+				// The device answers when it received the command
+				go func() {
+					select {
+					case <-received:
+						tt.device(d)
+					case <-time.After(time.Second):
+					}
+				}()
+			}
+
+			start := time.Now()
+			result, err := d.sendTelnetCommandAndWait(tt.cmd, tt.payload)
+
+			if result != tt.wantResult {
+				t.Errorf("sendTelnetCommandAndWait(%s, %s) = %s, want %s", tt.cmd, tt.payload, result, tt.wantResult)
+			}
+			if (err != nil) != (tt.wantResult != DenonCommandResultAccepted) {
+				t.Errorf("sendTelnetCommandAndWait(%s, %s) returned error %v", tt.cmd, tt.payload, err)
+			}
+			if code := result.StatusCode(); code != tt.wantStatusCode {
+				t.Errorf("status code = %d, want %d", code, tt.wantStatusCode)
+			}
+			if tt.wantResult == DenonCommandResultTimedOut && time.Since(start) < TELNET_ACKNOWLEDGEMENT_TIMEOUT {
+				t.Errorf("timed out after %s, want %s", time.Since(start), TELNET_ACKNOWLEDGEMENT_TIMEOUT)
+			}
+
+			d.acknowledgementMutex.Lock()
+			defer d.acknowledgementMutex.Unlock()
+			if len(d.telnetAcknowledgements) != 0 {
+				t.Errorf("%d commands still waiting for their echo", len(d.telnetAcknowledgements))
+			}
+		})
+	}
+}
+
+func TestTelnetReconnectBackOff(t *testing.T) {
+
+	minInterval := time.Duration(float64(TELNET_RECONNECT_INITIAL_INTERVAL) * (1 - TELNET_RECONNECT_JITTER))
+	maxInterval := time.Duration(float64(TELNET_RECONNECT_INITIAL_INTERVAL) * (1 + TELNET_RECONNECT_JITTER))
+
+	// The jitter is random, check enough backoffs to notice the default initial interval of the library
+	for i := 0; i < 100; i++ {
+		reconnectBackOff := newTelnetReconnectBackOff()
+
+		if interval := reconnectBackOff.NextBackOff(); interval < minInterval || interval > maxInterval {
+			t.Fatalf("first reconnect after %s, want between %s and %s", interval, minInterval, maxInterval)
+		}
+
+		// The interval grows up to the max interval
+		for j := 0; j < 20; j++ {
+			if interval := reconnectBackOff.NextBackOff(); interval > time.Duration(float64(TELNET_RECONNECT_MAX_INTERVAL)*(1+TELNET_RECONNECT_JITTER)) {
+				t.Fatalf("reconnect after %s, want at most %s with jitter", interval, TELNET_RECONNECT_MAX_INTERVAL)
+			}
+		}
+		// A stable connection starts with the initial interval again
+		reconnectBackOff.Reset()
+
+		if interval := reconnectBackOff.NextBackOff(); interval < minInterval || interval > maxInterval {
+			t.Fatalf("first reconnect after reset after %s, want between %s and %s", interval, minInterval, maxInterval)
+		}
+	}
+}
+
+func TestGetTelnetAddress(t *testing.T) {
+
+	tests := []struct {
+		host string
+		want string
+	}{
+		{"192.168.1.10", "192.168.1.10:23"},
+		{"192.168.1.10:10443", "192.168.1.10:23"},
+		{"denon.local:8080", "denon.local:23"},
+		{"[fe80::1]:8080", "[fe80::1]:23"},
+	}
+
+	for _, tt := range tests {
+		d := NewDenonAVR(tt.host, true, false)
+		if got := d.getTelnetAddress(); got != tt.want {
+			t.Errorf("getTelnetAddress() of host %s = %s, want %s", tt.host, got, tt.want)
+		}
+	}
+}
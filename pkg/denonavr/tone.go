@@ -0,0 +1,115 @@
+package denonavr
+
+import (
+	"fmt"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Bass and treble are sent relative to 50 (0dB) and can be set from -6dB to +6dB in 1dB steps
+const (
+	TONE_OFFSET float64 = 50
+	TONE_MIN    float64 = -6
+	TONE_MAX    float64 = 6
+	TONE_STEP   float64 = 1
+)
+
+func (d *DenonAVR) handleToneTelnetEvent(attribute string, param string) {
+
+	level, err := parseTelnetLevel(param, TONE_OFFSET)
+	if err != nil {
+		log.WithError(err).WithField("attribute", attribute).Error("failed to parse tone level")
+		return
+	}
+
+	d.SetAttribute(attribute, fmt.Sprintf("%0.1f", level))
+}
+
+func (d *DenonAVR) getToneLevel(attribute string) (float64, error) {
+
+	level, err := d.GetAttribute(attribute)
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseFloat(level.(string), 64)
+}
+
+func (d *DenonAVR) setToneLevel(parameter string, level float64) error {
+
+	if level < TONE_MIN || level > TONE_MAX {
+		return fmt.Errorf("tone level %0.1f out of range", level)
+	}
+
+	if !isLevelStep(level, TONE_STEP) {
+		return fmt.Errorf("tone level %0.1f is not a multiple of %0.1fdB", level, TONE_STEP)
+	}
+
+	_, err := d.sendCommandToDevice(DenonCommandPS, parameter+" "+formatTelnetVolume(level+TONE_OFFSET))
+	return err
+}
+
+// Return the bass level in dB
+func (d *DenonAVR) GetBass() (float64, error) {
+	return d.getToneLevel("Bass")
+}
+
+// Set the bass level in dB, e.g. PSBAS 53 for +3dB
+func (d *DenonAVR) SetBass(level float64) error {
+	return d.setToneLevel("BAS", level)
+}
+
+func (d *DenonAVR) BassUp() error {
+	_, err := d.sendCommandToDevice(DenonCommandPS, "BAS UP")
+	return err
+}
+
+func (d *DenonAVR) BassDown() error {
+	_, err := d.sendCommandToDevice(DenonCommandPS, "BAS DOWN")
+	return err
+}
+
+// Return the treble level in dB
+func (d *DenonAVR) GetTreble() (float64, error) {
+	return d.getToneLevel("Treble")
+}
+
+// Set the treble level in dB, e.g. PSTRE 47 for -3dB
+func (d *DenonAVR) SetTreble(level float64) error {
+	return d.setToneLevel("TRE", level)
+}
+
+func (d *DenonAVR) TrebleUp() error {
+	_, err := d.sendCommandToDevice(DenonCommandPS, "TRE UP")
+	return err
+}
+
+func (d *DenonAVR) TrebleDown() error {
+	_, err := d.sendCommandToDevice(DenonCommandPS, "TRE DOWN")
+	return err
+}
+
+// Tone control must be enabled to adjust bass and treble
+// With tone control disabled (tone defeat), bass and treble are bypassed
+func (d *DenonAVR) ToneControlEnabled() bool {
+
+	toneControl, err := d.GetAttribute("ToneControl")
+	if err != nil {
+		log.WithError(err).Debug("ToneControl attribute not found")
+		return false
+	}
+
+	return toneControl.(string) == "ON"
+}
+
+func (d *DenonAVR) SetToneControl(enabled bool) error {
+
+	state := "OFF"
+	if enabled {
+		state = "ON"
+	}
+
+	_, err := d.sendCommandToDevice(DenonCommandPS, "TONE CTRL "+state)
+	return err
+}
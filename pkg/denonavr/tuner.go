@@ -0,0 +1,245 @@
+package denonavr
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type DenonTunerBand string
+
+const (
+	DenonTunerBandAM DenonTunerBand = "AM"
+	DenonTunerBandFM DenonTunerBand = "FM"
+)
+
+// FM frequencies are set in MHz, AM frequencies in kHz
+// Both are sent with six digits, TFAN105000 for 105.00MHz, TFAN000522 for 522kHz
+const (
+	TUNER_FM_MIN float64 = 87.5
+	TUNER_FM_MAX float64 = 108
+	TUNER_AM_MIN float64 = 522
+	TUNER_AM_MAX float64 = 1710
+
+	// Sent frequencies from this value are FM frequencies
+	TUNER_FM_THRESHOLD int = 50000
+
+	TUNER_PRESETS int = 56
+)
+
+// Handle the tuner frequency and station name events
+// TFAN105000, TFANNAMEBBC Radio 1
+func (d *DenonAVR) handleTunerFrequencyTelnetEvent(param string) {
+
+	switch {
+	case strings.HasPrefix(param, "ANNAME"):
+		d.SetAttribute("TunerStationName", strings.TrimSpace(strings.TrimPrefix(param, "ANNAME")))
+	case strings.HasPrefix(param, "AN"):
+		frequency, err := strconv.Atoi(strings.TrimPrefix(param, "AN"))
+		if err != nil {
+			log.WithError(err).Debug("failed to parse tuner frequency")
+			return
+		}
+		band := DenonTunerBandAM
+		formattedFrequency := fmt.Sprintf("%d", frequency)
+		if frequency >= TUNER_FM_THRESHOLD {
+			band = DenonTunerBandFM
+			formattedFrequency = fmt.Sprintf("%0.2f", float64(frequency)/1000)
+		}
+		// The station name belongs to the previous frequency, a new one is sent if available
+		if current, err := d.GetAttribute("TunerFrequency"); err != nil || current.(string) != formattedFrequency {
+			d.SetAttribute("TunerStationName", "")
+		}
+		d.SetAttribute("TunerBand", string(band))
+		d.SetAttribute("TunerFrequency", formattedFrequency)
+	}
+
+	d.getMediaTitle()
+}
+
+// Handle the tuner preset events, the preset is OFF when the frequency is not stored as preset
+// TPAN01, TPANOFF
+func (d *DenonAVR) handleTunerPresetTelnetEvent(param string) {
+
+	preset := strings.TrimPrefix(param, "AN")
+	if preset == "OFF" {
+		d.SetAttribute("TunerPreset", 0)
+	} else if p, err := strconv.Atoi(preset); err == nil {
+		d.SetAttribute("TunerPreset", p)
+	} else {
+		return
+	}
+
+	d.getMediaTitle()
+}
+
+// Check if a telnet event is a tuner preset name
+func isTelnetTunerPresetNameEvent(data string) bool {
+	return strings.HasPrefix(data, string(DenonCommandTunerPresetName))
+}
+
+// Handle the tuner preset name events, sent for all presets after a query
+// OPTPN01BBC R1, OPTPN02 87.50
+func (d *DenonAVR) handleTunerPresetNameTelnetEvent(param string) {
+
+	if len(param) < 2 {
+		return
+	}
+
+	preset, err := strconv.Atoi(param[:2])
+	if err != nil {
+		log.WithError(err).Debug("failed to parse tuner preset name")
+		return
+	}
+
+	d.SetAttribute(fmt.Sprintf("TunerPresetName%02d", preset), strings.TrimSpace(param[2:]))
+
+	d.getMediaTitle()
+}
+
+// Return the name of a tuner preset, empty if unknown
+func (d *DenonAVR) GetTunerPresetName(preset int) string {
+
+	if name, err := d.GetAttribute(fmt.Sprintf("TunerPresetName%02d", preset)); err == nil {
+		return name.(string)
+	}
+
+	return ""
+}
+
+// Handle the tuner band events, the mode (AUTO, MANUAL) is ignored
+// TMANFM, TMANAM, TMANAUTO
+func (d *DenonAVR) handleTunerModeTelnetEvent(param string) {
+
+	band := DenonTunerBand(strings.TrimPrefix(param, "AN"))
+	if band == DenonTunerBandAM || band == DenonTunerBandFM {
+		d.SetAttribute("TunerBand", string(band))
+	}
+}
+
+// Return the title shown while the tuner is the active input
+// The RDS station name if available, then the preset name, otherwise the frequency with the preset
+func (d *DenonAVR) getTunerTitle() string {
+
+	if name, err := d.GetAttribute("TunerStationName"); err == nil && name.(string) != "" {
+		return name.(string)
+	}
+
+	if preset, err := d.GetAttribute("TunerPreset"); err == nil && preset.(int) > 0 {
+		if name := d.GetTunerPresetName(preset.(int)); name != "" {
+			return name
+		}
+	}
+
+	frequency, err := d.GetAttribute("TunerFrequency")
+	if err != nil {
+		return ""
+	}
+
+	title := frequency.(string) + " kHz"
+	if band, err := d.GetAttribute("TunerBand"); err == nil && band.(string) == string(DenonTunerBandFM) {
+		title = frequency.(string) + " MHz"
+	}
+
+	if preset, err := d.GetAttribute("TunerPreset"); err == nil && preset.(int) > 0 {
+		title = fmt.Sprintf("%s (%d)", title, preset.(int))
+	}
+
+	return title
+}
+
+func (d *DenonAVR) GetTunerBand() (DenonTunerBand, error) {
+
+	band, err := d.GetAttribute("TunerBand")
+	if err != nil {
+		return "", err
+	}
+
+	return DenonTunerBand(band.(string)), nil
+}
+
+// TMANFM
+func (d *DenonAVR) SetTunerBand(band DenonTunerBand) error {
+
+	if band != DenonTunerBandAM && band != DenonTunerBandFM {
+		return fmt.Errorf("invalid tuner band %s", band)
+	}
+
+	_, err := d.sendCommandToDevice(DenonCommandTunerMode, "AN"+string(band))
+	return err
+}
+
+// Return the tuner frequency, in MHz for FM and kHz for AM
+func (d *DenonAVR) GetTunerFrequency() (float64, error) {
+
+	frequency, err := d.GetAttribute("TunerFrequency")
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseFloat(frequency.(string), 64)
+}
+
+// Set the tuner frequency, in MHz for FM and kHz for AM
+// The band is selected by the frequency, TFAN105000 for 105.00MHz, TFAN000522 for 522kHz
+func (d *DenonAVR) SetTunerFrequency(frequency float64) error {
+
+	var payload string
+
+	switch {
+	case frequency >= TUNER_FM_MIN && frequency <= TUNER_FM_MAX:
+		payload = fmt.Sprintf("%06d", int(math.Round(frequency*1000)))
+	case frequency >= TUNER_AM_MIN && frequency <= TUNER_AM_MAX:
+		payload = fmt.Sprintf("%06d", int(math.Round(frequency)))
+	default:
+		return fmt.Errorf("tuner frequency %0.2f out of range", frequency)
+	}
+
+	_, err := d.sendCommandToDevice(DenonCommandTunerFrequency, "AN"+payload)
+	return err
+}
+
+func (d *DenonAVR) TunerFrequencyUp() error {
+	_, err := d.sendCommandToDevice(DenonCommandTunerFrequency, "ANUP")
+	return err
+}
+
+func (d *DenonAVR) TunerFrequencyDown() error {
+	_, err := d.sendCommandToDevice(DenonCommandTunerFrequency, "ANDOWN")
+	return err
+}
+
+// Return the active tuner preset, 0 if the frequency is not stored as preset
+func (d *DenonAVR) GetTunerPreset() (int, error) {
+
+	preset, err := d.GetAttribute("TunerPreset")
+	if err != nil {
+		return 0, err
+	}
+
+	return preset.(int), nil
+}
+
+// Recall a tuner preset from 1 to 56, e.g. TPAN01
+func (d *DenonAVR) RecallTunerPreset(preset int) error {
+
+	if preset < 1 || preset > TUNER_PRESETS {
+		return fmt.Errorf("invalid tuner preset %d", preset)
+	}
+
+	_, err := d.sendCommandToDevice(DenonCommandTunerPreset, fmt.Sprintf("AN%02d", preset))
+	return err
+}
+
+func (d *DenonAVR) TunerPresetUp() error {
+	_, err := d.sendCommandToDevice(DenonCommandTunerPreset, "ANUP")
+	return err
+}
+
+func (d *DenonAVR) TunerPresetDown() error {
+	_, err := d.sendCommandToDevice(DenonCommandTunerPreset, "ANDOWN")
+	return err
+}
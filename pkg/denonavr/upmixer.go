@@ -0,0 +1,69 @@
+package denonavr
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Upmixer and virtualizer options available within the Dolby and DTS surround modes
+type DenonUpmixerOption string
+
+const (
+	DenonUpmixerOptionSpeakerVirtualizer   DenonUpmixerOption = "SPV"
+	DenonUpmixerOptionNeuralX              DenonUpmixerOption = "NEURAL"
+	DenonUpmixerOptionCenterSpread         DenonUpmixerOption = "CES"
+	DenonUpmixerOptionHeightVirtualization DenonUpmixerOption = "HEG"
+)
+
+var UPMIXER_OPTION_MAPPING = map[string]DenonUpmixerOption{
+	"SPEAKER_VIRTUALIZER":   DenonUpmixerOptionSpeakerVirtualizer,
+	"NEURAL_X":              DenonUpmixerOptionNeuralX,
+	"CENTER_SPREAD":         DenonUpmixerOptionCenterSpread,
+	"HEIGHT_VIRTUALIZATION": DenonUpmixerOptionHeightVirtualization,
+}
+
+// Name of the attribute the state of an upmixer option is stored in
+var UPMIXER_OPTION_ATTRIBUTES = map[DenonUpmixerOption]string{
+	DenonUpmixerOptionSpeakerVirtualizer:   "SpeakerVirtualizer",
+	DenonUpmixerOptionNeuralX:              "NeuralX",
+	DenonUpmixerOptionCenterSpread:         "CenterSpread",
+	DenonUpmixerOptionHeightVirtualization: "HeightVirtualization",
+}
+
+// PSSPV ON, PSNEURAL OFF, PSCES ON, PSHEG OFF
+func (d *DenonAVR) handleUpmixerTelnetEvent(option DenonUpmixerOption, param string) {
+	d.SetAttribute(UPMIXER_OPTION_ATTRIBUTES[option], param)
+}
+
+// Return if a upmixer option is enabled
+// Options not available in the current surround mode are reported as disabled
+func (d *DenonAVR) UpmixerOptionEnabled(option DenonUpmixerOption) bool {
+
+	state, err := d.GetAttribute(UPMIXER_OPTION_ATTRIBUTES[option])
+	if err != nil {
+		log.WithError(err).WithField("option", string(option)).Debug("Upmixer option attribute not found")
+		return false
+	}
+
+	return state.(string) == "ON"
+}
+
+func (d *DenonAVR) SetUpmixerOption(option DenonUpmixerOption, enabled bool) error {
+
+	if _, ok := UPMIXER_OPTION_ATTRIBUTES[option]; !ok {
+		return fmt.Errorf("invalid upmixer option %s", option)
+	}
+
+	state := "OFF"
+	if enabled {
+		state = "ON"
+	}
+
+	_, err := d.sendCommandToDevice(DenonCommandPS, string(option)+" "+state)
+	return err
+}
+
+func (d *DenonAVR) ToggleUpmixerOption(option DenonUpmixerOption) error {
+	return d.SetUpmixerOption(option, !d.UpmixerOptionEnabled(option))
+}
@@ -8,55 +8,85 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
-func (d *DenonAVR) SetVolume(volume float64) error {
+// Return the command for the volume of a zone
+// MV50 for the main zone, Z250 for Zone2
+func (d *DenonAVR) getVolumeCommand(zone DenonZone) DenonCommand {
+	if zone == MainZone {
+		return DenonCommandMainZoneVolume
+	}
+
+	return d.getZoneCommand(zone)
+}
 
-	// The Volume command need the following
-	// 10.5 -> MV105
-	// 11 -> MV11
+// Return the command and the payload prefix for the mute of a zone
+// MUON for the main zone, Z2MUON for Zone2
+func (d *DenonAVR) getMuteCommand(zone DenonZone) (DenonCommand, string) {
+	if zone == MainZone {
+		return DenonCommandMainZoneMute, ""
+	}
+
+	return d.getZoneCommand(zone), string(DenonCommandMainZoneMute)
+}
 
-	var convertedVolume string
+// Convert a volume to the format used by the commands
+// The Volume command need the following
+// 10.5 -> MV105
+// 11 -> MV11
+func formatTelnetVolume(volume float64) string {
 
 	if volume != math.Trunc(volume) {
-		convertedVolume = strings.TrimRight(strings.TrimRight(fmt.Sprintf("%f", volume*10), "0"), ".")
-	} else {
-		convertedVolume = strings.TrimRight(strings.TrimRight(fmt.Sprintf("%f", volume), "0"), ".")
+		return strings.TrimRight(strings.TrimRight(fmt.Sprintf("%f", volume*10), "0"), ".")
 	}
 
-	_, err := d.sendCommandToDevice(DenonCommandMainZoneVolume, convertedVolume)
+	return strings.TrimRight(strings.TrimRight(fmt.Sprintf("%f", volume), "0"), ".")
+}
+
+// Check if a level is a multiple of the step the device supports, e.g. 0.5dB
+func isLevelStep(level float64, step float64) bool {
+	return math.Mod(level, step) == 0
+}
+
+func (d *DenonAVR) SetVolume(zone DenonZone, volume float64) error {
+
+	_, err := d.sendCommandToDevice(d.getVolumeCommand(zone), formatTelnetVolume(volume))
 	return err
 
 }
 
-func (d *DenonAVR) MainZoneMute() error {
+func (d *DenonAVR) Mute(zone DenonZone) error {
 
-	_, err := d.sendCommandToDevice(DenonCommandMainZoneMute, "ON")
+	cmd, prefix := d.getMuteCommand(zone)
+	_, err := d.sendCommandToDevice(cmd, prefix+"ON")
 	return err
 }
 
-func (d *DenonAVR) MainZoneUnMute() error {
+func (d *DenonAVR) UnMute(zone DenonZone) error {
 
-	_, err := d.sendCommandToDevice(DenonCommandMainZoneMute, "OFF")
+	cmd, prefix := d.getMuteCommand(zone)
+	_, err := d.sendCommandToDevice(cmd, prefix+"OFF")
 	return err
 }
 
-func (d *DenonAVR) MainZoneMuteToggle() error {
+func (d *DenonAVR) MuteToggle(zone DenonZone) error {
 
-	if d.MainZoneMuted() {
-		return d.MainZoneUnMute()
+	if d.Muted(zone) {
+		return d.UnMute(zone)
 
 	}
-	return d.MainZoneMute()
+	return d.Mute(zone)
 }
 
-func (d *DenonAVR) MainZoneMuted() bool {
+func (d *DenonAVR) Muted(zone DenonZone) bool {
+
+	attribute := d.GetZoneName(zone) + "Mute"
 
-	mainZoneMute, err := d.GetAttribute("MainZoneMute")
+	zoneMute, err := d.GetAttribute(attribute)
 	if err != nil {
-		log.WithError(err).Debug("MainZoneMute attribute not found")
+		log.WithError(err).Debugf("%s attribute not found", attribute)
 		return false
 	}
 
-	switch mainZoneMute.(string) {
+	switch zoneMute.(string) {
 	case "on":
 		return true
 	default:
@@ -64,14 +94,14 @@ func (d *DenonAVR) MainZoneMuted() bool {
 	}
 }
 
-func (d *DenonAVR) SetVolumeUp() error {
+func (d *DenonAVR) SetVolumeUp(zone DenonZone) error {
 
-	_, err := d.sendCommandToDevice(DenonCommandMainZoneVolume, "UP")
+	_, err := d.sendCommandToDevice(d.getVolumeCommand(zone), "UP")
 	return err
 
 }
 
-func (d *DenonAVR) SetVolumeDown() error {
-	_, err := d.sendCommandToDevice(DenonCommandMainZoneVolume, "DOWN")
+func (d *DenonAVR) SetVolumeDown(zone DenonZone) error {
+	_, err := d.sendCommandToDevice(d.getVolumeCommand(zone), "DOWN")
 	return err
 }